@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import "image"
+
+// alphaHistogram counts the alpha values of img.
+func alphaHistogram(img *image.NRGBA) (hist [256]int, total int) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	for y := range height {
+		row := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		for i := 3; i < len(row); i += 4 {
+			hist[row[i]]++
+		}
+	}
+
+	return hist, width * height
+}
+
+// scaledAlpha returns a multiplied by scale, rounded and clamped to 0..255.
+func scaledAlpha(a int, scale float64) uint8 {
+	return uint8(min(255, float64(a)*scale+0.5))
+}
+
+// scaledCoverage returns the fraction of texels whose alpha, multiplied by scale,
+// is above ref.
+func scaledCoverage(hist *[256]int, total int, ref uint8, scale float64) float64 {
+	above := 0
+	for a, n := range hist {
+		if n > 0 && scaledAlpha(a, scale) > ref {
+			above += n
+		}
+	}
+
+	return float64(above) / float64(total)
+}
+
+// preserveAlphaCoverage rescales the alpha of mips[1:] in place so the fraction
+// of texels with alpha above ref matches mips[0], keeping alpha-tested
+// cutouts from thinning out or vanishing at distance.
+func preserveAlphaCoverage(mips []*image.NRGBA, ref uint8) {
+	if len(mips) < 2 {
+		return
+	}
+	hist, total := alphaHistogram(mips[0])
+	target := scaledCoverage(&hist, total, ref, 1)
+
+	for _, mip := range mips[1:] {
+		hist, total := alphaHistogram(mip)
+
+		// Coverage grows with the scale; bisect for the closest match.
+		lo, hi := 0.0, 256.0
+		for range 24 {
+			mid := (lo + hi) / 2
+			if scaledCoverage(&hist, total, ref, mid) < target {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		scale := hi
+		if target-scaledCoverage(&hist, total, ref, lo) < scaledCoverage(&hist, total, ref, hi)-target {
+			scale = lo
+		}
+
+		var lut [256]uint8
+		for a := range lut {
+			lut[a] = scaledAlpha(a, scale)
+		}
+		width, height := mip.Rect.Dx(), mip.Rect.Dy()
+		for y := range height {
+			row := mip.Pix[y*mip.Stride : y*mip.Stride+width*4]
+			for i := 3; i < len(row); i += 4 {
+				row[i] = lut[row[i]]
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package edds
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func noisyAlphaNRGBA(size int) *image.NRGBA {
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // deterministic test data
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			img.SetNRGBA(x, y, color.NRGBA{R: 40, G: 160, B: 30, A: uint8(rng.IntN(256))}) //nolint:gosec // bounded
+		}
+	}
+
+	return img
+}
+
+func alphaCoverageOf(img *image.NRGBA, ref uint8) float64 {
+	hist, total := alphaHistogram(img)
+	return scaledCoverage(&hist, total, ref, 1)
+}
+
+func TestPreserveAlphaCoverage(t *testing.T) {
+	t.Parallel()
+
+	const ref = 180
+	img := noisyAlphaNRGBA(64)
+	target := alphaCoverageOf(img, ref)
+
+	plain := generateMipmaps(nil, img, 5, &WriteOptions{})
+	if drift := target - alphaCoverageOf(plain[3], ref); drift < 0.2 {
+		t.Fatalf("plain mips keep coverage (drift %.3f); test input too weak", drift)
+	}
+
+	base := bytes.Clone(img.Pix)
+	mips := generateMipmaps(nil, img, 5, &WriteOptions{AlphaCoverageRef: ref})
+	if !bytes.Equal(img.Pix, base) {
+		t.Fatal("level 0 was modified")
+	}
+	for level, mip := range mips[1:] {
+		if got := alphaCoverageOf(mip, ref); math.Abs(got-target) > 0.03 {
+			t.Fatalf("level %d coverage %.3f, want %.3f", level+1, got, target)
+		}
+		if c := mip.NRGBAAt(0, 0); c.R != 40 || c.G != 160 || c.B != 30 {
+			t.Fatalf("level %d color changed: %v", level+1, c)
+		}
+	}
+}
+
+func TestEncodeWithAlphaCoverage(t *testing.T) {
+	t.Parallel()
+
+	const ref = 180
+	img := noisyAlphaNRGBA(32)
+	opts := &WriteOptions{Format: bcn.FormatBGRA8, MipFilter: MipFilterTriangle, AlphaCoverageRef: ref}
+
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, img, opts); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	mip, err := DecodeMip(bytes.NewReader(buf.Bytes()), 2, nil)
+	if err != nil {
+		t.Fatalf("DecodeMip: %v", err)
+	}
+	if got, want := alphaCoverageOf(mip.(*image.NRGBA), ref), alphaCoverageOf(img, ref); math.Abs(got-want) > 0.05 {
+		t.Fatalf("decoded level 2 coverage %.3f, want %.3f", got, want)
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// Texture2DArray holds the slices of a 2D texture array.
+// EDDS stores one block per mip level holding every slice in array order.
+type Texture2DArray struct {
+	// Slices holds a mip chain per array slice,
+	// ordered from largest (level 0) to smallest.
+	// Writers generate mipmaps from WriteOptions when every slice holds only level 0;
+	// otherwise every slice must hold a chain of the same length, which is written
+	// as given like EncodeMipImages does.
+	Slices [][]image.Image
+	// Format is the stored pixel format; it is set by readers and ignored by writers.
+	Format bcn.Format
+}
+
+// ReadArray reads and decodes every slice and mip level of an EDDS texture array file.
+func ReadArray(path string, opts *ReadOptions) (*Texture2DArray, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := validateInputFileSize(f, limits); err != nil {
+		return nil, err
+	}
+
+	return NewDecoder().DecodeArray(f, opts)
+}
+
+// DecodeArray reads and decodes every slice and mip level of an EDDS texture array stream.
+func DecodeArray(r io.Reader, opts *ReadOptions) (*Texture2DArray, error) {
+	return NewDecoder().DecodeArray(r, opts)
+}
+
+// DecodeArray reads and decodes every slice and mip level of an EDDS texture array stream.
+// A plain 2D texture is returned as a one-slice array.
+// Returned images own their pixel buffers and stay valid after later calls on the same Decoder.
+func (d *Decoder) DecodeArray(r io.Reader, opts *ReadOptions) (*Texture2DArray, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	decOpts := (*bcn.DecodeOptions)(nil)
+	if opts != nil {
+		decOpts = opts.DecodeOptions
+	}
+
+	array := &Texture2DArray{}
+	var linearize bool
+	err = d.readMipChain(r, limits, func(chain *mipChain) error {
+		if chain.layout.faces != 1 || chain.layout.volume {
+			return fmt.Errorf("%w: %s is not a texture array", ErrUnsupportedTextureType, chain.layout.describe())
+		}
+		array.Format = chain.format
+		linearize = linearizes(opts, chain.dx10)
+		array.Slices = make([][]image.Image, chain.layout.arraySize)
+		for slice := range array.Slices {
+			array.Slices[slice] = make([]image.Image, chain.mipMapCount)
+		}
+		return nil
+	}, func(level, width, height int, payload []byte) error {
+		sliceSize := len(payload) / len(array.Slices)
+		for slice := range array.Slices {
+			img, err := decodeImage(payload[slice*sliceSize:(slice+1)*sliceSize], width, height, array.Format, decOpts)
+			if err != nil {
+				return fmt.Errorf("%w: slice %d mipmap %d: %v", ErrDecodeImage, slice, level, err)
+			}
+			if linearize {
+				img = linearizeImage(img)
+			}
+			array.Slices[slice][level] = img
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return array, nil
+}
+
+// WriteArray writes an EDDS texture array file.
+func WriteArray(array *Texture2DArray, path string, opts *WriteOptions) error {
+	enc := NewEncoder()
+	return writeFileAtomic(path, func(f *os.File) error {
+		return enc.EncodeArray(NewStreamWriter(f), array, opts)
+	})
+}
+
+// EncodeArray writes an EDDS texture array stream.
+func EncodeArray(w io.Writer, array *Texture2DArray, opts *WriteOptions) error {
+	return NewEncoder().EncodeArray(w, array, opts)
+}
+
+// EncodeArray writes an EDDS texture array stream with a DX10 header.
+// Level 0 of every slice must have the same size.
+// Slices holding only level 0 get mipmaps generated according to opts;
+// slices holding full chains are written as given, see Texture2DArray.Slices.
+// A one-slice array is written as a plain 2D texture.
+func (e *Encoder) EncodeArray(w io.Writer, array *Texture2DArray, opts *WriteOptions) error {
+	width, height, err := array.sliceSize()
+	if err != nil {
+		return err
+	}
+
+	cfg := normalizeWriteOptions(opts)
+	if err := validateWriteOptions(&cfg); err != nil {
+		return err
+	}
+
+	levels, err := e.encodeChains(array.Slices, width, height, &cfg, ErrInvalidTextureArray, "slice")
+	if err != nil {
+		return err
+	}
+
+	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
+	if err != nil {
+		return err
+	}
+
+	layout := textureLayout{faces: 1, arraySize: len(array.Slices), depth: 1}
+	return e.writeFromBlocks(w, cfg.Format, cfg.ColorSpace, width, height, layout, levels, compression, cfg.Concurrency)
+}
+
+// sliceSize validates level 0 of every slice and returns the shared dimensions.
+func (a *Texture2DArray) sliceSize() (int, int, error) {
+	if a == nil || len(a.Slices) == 0 {
+		return 0, 0, fmt.Errorf("%w: no slices", ErrInvalidTextureArray)
+	}
+	if len(a.Slices) > maxTextureSlices {
+		return 0, 0, fmt.Errorf("%w: %d slices exceeds %d", ErrInvalidTextureArray, len(a.Slices), maxTextureSlices)
+	}
+
+	return sharedImageSize(baseLevels(a.Slices), ErrInvalidTextureArray, "slice")
+}
+
+// encodeChains encodes one mip chain per surface and joins each level of every
+// surface into one block payload, in surface order. Chains longer than one level
+// are written as given, capped by MaxMipMaps; otherwise mipmaps are generated
+// from level 0. name labels a surface in errors.
+func (e *Encoder) encodeChains(
+	chains [][]image.Image,
+	width, height int,
+	cfg *WriteOptions,
+	errInvalid error,
+	name string,
+) ([][]byte, error) {
+	supplied, err := suppliedChainLevels(chains, errInvalid, name)
+	if err != nil {
+		return nil, err
+	}
+	mipMapCount, err := writeMipMapCount(width, height, cfg.MaxMipMaps)
+	if err != nil {
+		return nil, err
+	}
+	if supplied > 1 {
+		mipMapCount = supplied
+		if cfg.MaxMipMaps > 0 {
+			mipMapCount = min(mipMapCount, cfg.MaxMipMaps)
+		}
+	}
+
+	levels := make([][]byte, mipMapCount)
+	for i, mips := range chains {
+		var payloads [][]byte
+		if supplied > 1 {
+			payloads, err = e.encodeMipImages(mips[:mipMapCount], cfg)
+		} else {
+			payloads, err = e.encodeMipPayloads(mips[0], mipMapCount, cfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", name, i, err)
+		}
+		for level, payload := range payloads {
+			levels[level] = append(levels[level], payload...)
+		}
+	}
+
+	return levels, nil
+}
+
+// suppliedChainLevels returns the number of mip levels every chain holds.
+// Chains must all hold the same number of levels, and chains longer than
+// one level must halve like EncodeMipImages expects.
+func suppliedChainLevels(chains [][]image.Image, errInvalid error, name string) (int, error) {
+	count := len(chains[0])
+	for i, mips := range chains {
+		if len(mips) != count {
+			return 0, fmt.Errorf("%w: %s %d has %d levels, %s 0 has %d", errInvalid, name, i, len(mips), name, count)
+		}
+		if count > 1 {
+			if _, _, err := validateMipImages(mips); err != nil {
+				return 0, fmt.Errorf("%w: %s %d: %w", errInvalid, name, i, err)
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// sharedImageSize checks that every image is present
+// and has the same non-empty size, and returns that size.
+func sharedImageSize(images []image.Image, errInvalid error, name string) (int, int, error) {
+	var width, height int
+	for i, img := range images {
+		if img == nil {
+			return 0, 0, fmt.Errorf("%w: %s %d has no image", errInvalid, name, i)
+		}
+
+		bounds := img.Bounds()
+		if bounds.Empty() {
+			return 0, 0, fmt.Errorf("%w: %s %d is empty", errInvalid, name, i)
+		}
+		if i == 0 {
+			width, height = bounds.Dx(), bounds.Dy()
+		} else if bounds.Dx() != width || bounds.Dy() != height {
+			return 0, 0, fmt.Errorf("%w: %s %d is %dx%d, want %dx%d", errInvalid, name, i, bounds.Dx(), bounds.Dy(), width, height)
+		}
+	}
+
+	return width, height, nil
+}
+
+// baseLevels returns level 0 of every mip chain, or nil for empty chains.
+func baseLevels(chains [][]image.Image) []image.Image {
+	base := make([]image.Image, len(chains))
+	for i, mips := range chains {
+		if len(mips) > 0 {
+			base[i] = mips[0]
+		}
+	}
+
+	return base
+}
@@ -0,0 +1,96 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestArrayRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	array := &Texture2DArray{}
+	for _, c := range cubeFaceColors[:3] {
+		array.Slices = append(array.Slices, []image.Image{solidImage(8, c)})
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeArray(&buf, array, &WriteOptions{Format: bcn.FormatDXT5}); err != nil {
+		t.Fatalf("EncodeArray: %v", err)
+	}
+
+	info, err := Inspect(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.DX10 == nil || info.DX10.ArraySize != 3 || info.DX10.ResourceDimension != dx10ResourceDimensionTexture2D ||
+		info.Format != bcn.FormatDXT5 {
+		t.Fatalf("DX10 header = %+v, format %v", info.DX10, info.Format)
+	}
+	level0 := info.Blocks[len(info.Blocks)-1]
+	if level0.UncompressedSize != int32(3*expectedDataLength(bcn.FormatDXT5, 8, 8)) {
+		t.Fatalf("level 0 block holds %d bytes, want 3 slices", level0.UncompressedSize)
+	}
+
+	got, err := DecodeArray(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeArray: %v", err)
+	}
+	if len(got.Slices) != 3 {
+		t.Fatalf("DecodeArray returned %d slices, want 3", len(got.Slices))
+	}
+	for slice, mips := range got.Slices {
+		if len(mips) != 4 {
+			t.Fatalf("slice %d has %d levels, want 4", slice, len(mips))
+		}
+		if c := color.NRGBAModel.Convert(mips[0].At(0, 0)); c != cubeFaceColors[slice] {
+			t.Fatalf("slice %d color = %v, want %v", slice, c, cubeFaceColors[slice])
+		}
+	}
+
+	if _, err := Decode(bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrUnsupportedTextureType) {
+		t.Fatalf("Decode array error = %v, want ErrUnsupportedTextureType", err)
+	}
+}
+
+func TestEncodeArraySuppliedChains(t *testing.T) {
+	t.Parallel()
+
+	// Level 1 is black in every slice, which generated mipmaps would never produce.
+	colors := []color.NRGBA{{R: 255, A: 255}, {G: 255, A: 255}}
+	array := &Texture2DArray{}
+	for _, c := range colors {
+		array.Slices = append(array.Slices, []image.Image{solidImage(8, c), solidImage(4, color.NRGBA{A: 255}), solidImage(2, c)})
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeArray(&buf, array, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeArray: %v", err)
+	}
+	got, err := DecodeArray(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeArray: %v", err)
+	}
+	for slice, c := range colors {
+		mips := got.Slices[slice]
+		if len(mips) != 3 {
+			t.Fatalf("slice %d has %d levels, want 3", slice, len(mips))
+		}
+		if level1 := color.NRGBAModel.Convert(mips[1].At(0, 0)); level1 != (color.NRGBA{A: 255}) {
+			t.Fatalf("slice %d level 1 = %v, want supplied black", slice, level1)
+		}
+		if level2 := color.NRGBAModel.Convert(mips[2].At(0, 0)); level2 != c {
+			t.Fatalf("slice %d level 2 = %v, want %v", slice, level2, c)
+		}
+	}
+
+	// Partial chains are rejected rather than silently regenerated.
+	array.Slices[1] = array.Slices[1][:1]
+	if err := EncodeArray(&bytes.Buffer{}, array, nil); !errors.Is(err, ErrInvalidTextureArray) {
+		t.Fatalf("partial chains error = %v, want ErrInvalidTextureArray", err)
+	}
+}
@@ -0,0 +1,306 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BatchOptions configures ConvertBatch and ConvertDir.
+type BatchOptions struct {
+	// WriteOptions are applied to every file. Nil uses the Write defaults.
+	WriteOptions *WriteOptions
+	// Match selects source files by slash-separated path relative to the root.
+	// Nil matches .png, .jpg and .jpeg files, ignoring case.
+	// Inputs are decoded with image.Decode, so the caller must register
+	// a decoder for every matched format, for example by importing
+	// image/png and image/jpeg; files without one fail with ErrDecodeImage.
+	Match func(path string) bool
+	// Progress is called once per finished file. Calls are serialized
+	// and made from the goroutine running ConvertBatch.
+	Progress func(BatchResult)
+	// Manifest enables incremental rebuilds when set to a file path.
+	// The manifest records a source content hash and the normalized write
+	// options per output, and the output root. Sources whose hash and options are
+	// unchanged and whose output exists are skipped, and outputs whose source
+	// disappeared are deleted. A manifest written for another output directory
+	// is ignored: every source is rebuilt and nothing is deleted.
+	// It is rewritten after every run, including canceled ones.
+	Manifest string
+	// Workers is the number of files converted in parallel,
+	// each worker owning its own Encoder. Zero uses GOMAXPROCS.
+	Workers int
+}
+
+// BatchResult reports the outcome of one file.
+type BatchResult struct {
+	// Err is the conversion error, or nil on success.
+	Err error
+	// Source is the slash-separated path relative to the source root.
+	Source string
+	// Output is the written EDDS path.
+	Output string
+	// Done is the number of finished files including this one.
+	Done int
+	// Total is the number of matched files.
+	Total int
+	// Skipped is true when the output was current and left untouched.
+	Skipped bool
+
+	// hash is the source content hash in incremental mode.
+	hash string
+}
+
+// BatchReport summarizes a batch run.
+type BatchReport struct {
+	// Failed lists the results with errors in completion order.
+	// Failed stale output removals are appended after the conversions.
+	Failed []BatchResult
+	// Removed lists the stale outputs deleted in incremental mode.
+	Removed []string
+	// Converted is the number of files written successfully.
+	Converted int
+	// Skipped is the number of current outputs left untouched in incremental mode.
+	Skipped int
+	// Total is the number of matched files.
+	Total int
+}
+
+// ConvertDir converts every matching image under srcDir into EDDS files under dstDir.
+func ConvertDir(ctx context.Context, srcDir, dstDir string, opts *BatchOptions) (*BatchReport, error) {
+	return ConvertBatch(ctx, os.DirFS(srcDir), dstDir, opts)
+}
+
+// ConvertBatch walks fsys and converts every matching image into an EDDS file
+// under dstDir, mirroring the source tree and replacing the extension with ".edds".
+// Per-file errors are reported through opts.Progress and the returned report
+// without stopping the run; the returned error is reserved for invalid options
+// and context cancellation. Every output is written with an atomic replace.
+// See BatchOptions.Manifest for incremental rebuilds.
+func ConvertBatch(ctx context.Context, fsys fs.FS, dstDir string, opts *BatchOptions) (*BatchReport, error) {
+	var cfg BatchOptions
+	if opts != nil {
+		cfg = *opts
+	}
+	if cfg.Match == nil {
+		cfg.Match = matchImageExt
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.GOMAXPROCS(0)
+	}
+
+	// Validate shared write options once instead of failing every file the same way.
+	writeCfg := normalizeWriteOptions(cfg.WriteOptions)
+	if err := validateWriteOptions(&writeCfg); err != nil {
+		return nil, err
+	}
+	compression, err := normalizeCompressionOptions(writeCfg.Compression, writeCfg.Compress)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest *batchManifest
+	var cache *batchCache
+	if cfg.Manifest != "" {
+		// Entries are relative to the output root, so they only apply to the same one.
+		outputRoot, err := filepath.Abs(dstDir)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidManifest, dstDir, err)
+		}
+		if manifest, err = loadManifest(cfg.Manifest, outputRoot); err != nil {
+			return nil, err
+		}
+		options, err := writeOptionsHash(writeCfg, compression)
+		if err != nil {
+			return nil, err
+		}
+		cache = &batchCache{previous: manifest.Entries, options: options}
+	}
+
+	report := &BatchReport{}
+	var sources []BatchResult
+	var walkFailures []BatchResult
+	outputs := make(map[string]string)
+	err = fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			walkFailures = append(walkFailures, BatchResult{Source: name, Err: err})
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !cfg.Match(name) {
+			return nil
+		}
+
+		output := filepath.Join(dstDir, filepath.FromSlash(batchRelOutput(name)))
+		if first, ok := outputs[output]; ok {
+			walkFailures = append(walkFailures, BatchResult{
+				Source: name,
+				Output: output,
+				Err:    fmt.Errorf("%w: %q and %q both write %q", ErrBatchOutputConflict, first, name, output),
+			})
+			return nil
+		}
+		outputs[output] = name
+		sources = append(sources, BatchResult{Source: name, Output: output})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var next map[string]manifestEntry
+	if manifest != nil {
+		// Start from the previous entries of every current source so a canceled
+		// run keeps what it did not reach; finished files overwrite theirs.
+		next = make(map[string]manifestEntry, len(sources))
+		for _, source := range sources {
+			if entry, ok := manifest.Entries[source.Source]; ok {
+				next[source.Source] = entry
+			}
+		}
+	}
+
+	report.Total = len(sources) + len(walkFailures)
+	finish := func(result BatchResult) {
+		result.Done = report.Converted + report.Skipped + len(report.Failed) + 1
+		result.Total = report.Total
+		switch {
+		case result.Err != nil:
+			report.Failed = append(report.Failed, result)
+		case result.Skipped:
+			report.Skipped++
+		default:
+			report.Converted++
+		}
+		if next != nil && result.Output != "" {
+			// Failed files keep an entry without hashes, so they are
+			// retried next run and their output is still removed once stale.
+			entry := manifestEntry{Output: batchRelOutput(result.Source)}
+			if result.Err == nil {
+				entry.SourceHash = result.hash
+				entry.OptionsHash = cache.options
+			}
+			next[result.Source] = entry
+		}
+		if cfg.Progress != nil {
+			cfg.Progress(result)
+		}
+	}
+	for _, failure := range walkFailures {
+		finish(failure)
+	}
+
+	jobs := make(chan BatchResult)
+	results := make(chan BatchResult)
+	var wg sync.WaitGroup
+	for range min(cfg.Workers, max(1, len(sources))) {
+		wg.Go(func() {
+			enc := NewEncoder()
+			for job := range jobs {
+				results <- enc.convertFile(fsys, job, &writeCfg, cache)
+			}
+		})
+	}
+	go func() {
+		defer close(jobs)
+		for _, source := range sources {
+			select {
+			case jobs <- source:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		finish(result)
+	}
+
+	ctxErr := ctx.Err()
+	if manifest != nil {
+		// An unreadable directory hides its sources; do not mistake them for deleted ones.
+		keepStale := ctxErr != nil || slices.ContainsFunc(walkFailures, func(r BatchResult) bool {
+			return r.Output == ""
+		})
+		manifest.removeStale(dstDir, outputs, next, keepStale, report)
+		manifest.Entries = next
+		if err := manifest.save(cfg.Manifest); err != nil {
+			return report, err
+		}
+	}
+	if ctxErr != nil {
+		return report, ctxErr
+	}
+
+	return report, nil
+}
+
+// convertFile decodes job.Source from fsys and writes it to job.Output as EDDS.
+// With a cache the source is hashed first and skipped when its output is current.
+func (e *Encoder) convertFile(fsys fs.FS, job BatchResult, opts *WriteOptions, cache *batchCache) BatchResult {
+	data, err := fs.ReadFile(fsys, job.Source)
+	if err != nil {
+		job.Err = fmt.Errorf("%w: %q: %v", ErrOpenFile, job.Source, err)
+		return job
+	}
+	if cache != nil {
+		job.hash = sha256Hex(data)
+		if cache.upToDate(job) {
+			job.Skipped = true
+			return job
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		job.Err = fmt.Errorf("%w: %q: %v", ErrDecodeImage, job.Source, err)
+		return job
+	}
+	if err := os.MkdirAll(filepath.Dir(job.Output), 0o750); err != nil {
+		job.Err = fmt.Errorf("%w: %q: %v", ErrCreateFile, job.Output, err)
+		return job
+	}
+
+	job.Err = writeFileAtomic(job.Output, func(f *os.File) error {
+		return e.EncodeWithOptions(NewStreamWriter(f), img, opts)
+	})
+	return job
+}
+
+// batchRelOutput maps a slash-separated source path to its output path
+// relative to the output root.
+func batchRelOutput(source string) string {
+	return strings.TrimSuffix(source, path.Ext(source)) + ".edds"
+}
+
+// matchImageExt matches PNG and JPEG inputs.
+func matchImageExt(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	default:
+		return false
+	}
+}
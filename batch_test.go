@@ -0,0 +1,132 @@
+package edds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/woozymasta/bcn"
+)
+
+func batchPNG(t *testing.T, size int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 8), G: uint8(y * 8), B: 90, A: 255}) //nolint:gosec // bounded
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestConvertBatch(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"a.png":             {Data: batchPNG(t, 16)},
+		"nested/b.PNG":      {Data: batchPNG(t, 8)},
+		"nested/deep/c.png": {Data: batchPNG(t, 4)},
+		"broken.png":        {Data: []byte("not a png")},
+		"notes.txt":         {Data: []byte("skip me")},
+	}
+	dst := t.TempDir()
+
+	var progress []BatchResult
+	report, err := ConvertBatch(context.Background(), fsys, dst, &BatchOptions{
+		WriteOptions: &WriteOptions{Format: bcn.FormatDXT1},
+		Workers:      2,
+		Progress:     func(r BatchResult) { progress = append(progress, r) },
+	})
+	if err != nil {
+		t.Fatalf("ConvertBatch: %v", err)
+	}
+	if report.Total != 4 || report.Converted != 3 || len(report.Failed) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	failed := report.Failed[0]
+	if failed.Source != "broken.png" || !errors.Is(failed.Err, ErrDecodeImage) {
+		t.Fatalf("failed = %+v", failed)
+	}
+
+	if len(progress) != 4 {
+		t.Fatalf("progress calls = %d, want 4", len(progress))
+	}
+	for i, r := range progress {
+		if r.Done != i+1 || r.Total != 4 {
+			t.Fatalf("progress[%d] = %+v", i, r)
+		}
+	}
+
+	for name, size := range map[string]int{"a.edds": 16, "nested/b.edds": 8, "nested/deep/c.edds": 4} {
+		img, err := Read(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("Read %s: %v", name, err)
+		}
+		if img.Bounds().Dx() != size {
+			t.Fatalf("%s bounds = %v", name, img.Bounds())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "broken.edds")); !os.IsNotExist(err) {
+		t.Fatalf("broken output exists: %v", err)
+	}
+}
+
+func TestConvertDirOutputConflict(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	for _, name := range []string{"tex.png", "tex.jpg"} {
+		if err := os.WriteFile(filepath.Join(src, name), batchPNG(t, 4), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	report, err := ConvertDir(context.Background(), src, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("ConvertDir: %v", err)
+	}
+	if report.Converted != 1 || len(report.Failed) != 1 || !errors.Is(report.Failed[0].Err, ErrBatchOutputConflict) {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+func TestConvertBatchErrors(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{"a.png": {Data: batchPNG(t, 4)}}
+	_, err := ConvertBatch(context.Background(), fsys, t.TempDir(), &BatchOptions{
+		WriteOptions: &WriteOptions{Compression: CompressionOptions{Mode: CompressionMode(99)}},
+	})
+	if !errors.Is(err, ErrInvalidCompressionOptions) {
+		t.Fatalf("invalid options error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ConvertBatch(ctx, fsys, t.TempDir(), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled error = %v", err)
+	}
+
+	report, err := ConvertBatch(context.Background(), fsys, t.TempDir(), &BatchOptions{
+		Match: func(name string) bool { return strings.HasSuffix(name, ".tga") },
+	})
+	if err != nil || report.Total != 0 {
+		t.Fatalf("no matches: report %+v, err %v", report, err)
+	}
+}
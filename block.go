@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"slices"
 )
 
 const (
@@ -51,6 +52,38 @@ func writeBlockData(w io.Writer, block *Block) error {
 	return nil
 }
 
+// writeBlocks writes the block table followed by block bodies.
+// blocks are ordered from largest to smallest mip;
+// EDDS stores both the table and the bodies from smallest to largest.
+func writeBlocks(w io.Writer, blocks []*Block) error {
+	for i, block := range slices.Backward(blocks) {
+		if err := writeBlockHeader(w, i, blockHeader{Magic: block.Magic, Size: block.Size}); err != nil {
+			return err
+		}
+	}
+
+	// Payload order mirrors the table order.
+	for i, block := range slices.Backward(blocks) {
+		if err := writeBlockData(w, block); err != nil {
+			return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockData, i, err)
+		}
+	}
+
+	return nil
+}
+
+// writeBlockHeader writes the block table entry of mipmap level.
+func writeBlockHeader(w io.Writer, level int, h blockHeader) error {
+	if _, err := w.Write([]byte(h.Magic)); err != nil {
+		return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockMagic, level, err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, h.Size); err != nil {
+		return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockSize, level, err)
+	}
+
+	return nil
+}
+
 // readBlockTableInto reads block headers into a reusable slice.
 func readBlockTableInto(dst []blockHeader, r io.Reader, mipMapCount uint32) ([]blockHeader, error) {
 	hdrs := ensureBlockHeaderSlots(dst, int(mipMapCount))[:0]
@@ -94,12 +127,6 @@ func readBlockTable(r io.Reader, mipMapCount uint32) ([]blockHeader, error) {
 	return readBlockTableInto(nil, r, mipMapCount)
 }
 
-// readBlockBody reads one block body using a block table header.
-func readBlockBody(r io.Reader, h blockHeader) (*Block, error) {
-	block, _, err := readBlockBodyInto(nil, r, h)
-	return block, err
-}
-
 // readBlockBodyInto reads one block body into a reusable buffer.
 func readBlockBodyInto(dst []byte, r io.Reader, h blockHeader) (*Block, []byte, error) {
 	if h.Size < 0 {
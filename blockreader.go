@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// blockReader streams the decompressed payload of one EDDS block body from an
+// underlying reader. LZ4 bodies are inflated chunk by chunk with the rolling
+// 64 KiB dictionary, so only one compressed and one decoded chunk are held
+// instead of the whole block. It accepts the same bodies as decompressBlock
+// and reports the same errors, wrapped as ErrReadBlockBody or ErrDecompressBlock.
+// A blockReader is reusable through reset and is not safe for concurrent use.
+type blockReader struct {
+	src       io.Reader
+	magic     string
+	head      []byte // body bytes read ahead to detect the size prefix
+	dict      []byte
+	chunk     []byte
+	out       []byte
+	pending   []byte
+	err       error // sticky error returned by every later Read
+	remaining int64 // body bytes not yet read from src
+	index     int   // block table index used in errors
+	target    int
+	produced  int
+	dictSize  int
+	consumed  bool // any body byte was read from src
+	last      bool
+	ahead     [8]byte
+}
+
+// reset prepares b to read the body h of block table entry index from src.
+// expectedSize is the payload size computed from the texture dimensions.
+func (b *blockReader) reset(src io.Reader, index int, h blockHeader, expectedSize int) error {
+	*b = blockReader{
+		src:       src,
+		magic:     h.Magic,
+		dict:      b.dict,
+		chunk:     b.chunk,
+		out:       b.out,
+		remaining: int64(h.Size),
+		index:     index,
+		target:    expectedSize,
+	}
+
+	if h.Size < 0 {
+		b.err = fmt.Errorf("%w: mipmap %d: %v: %d", ErrReadBlockBody, index, ErrBlockBodyInvalidSize, h.Size)
+		return b.err
+	}
+	switch h.Magic {
+	case BlockMagicCOPY:
+		if int(h.Size) != expectedSize {
+			b.err = b.decodeError(fmt.Errorf("%w: expected %d, got %d", ErrCopySizeMismatch, expectedSize, h.Size))
+		}
+		return b.err
+	case BlockMagicLZ4:
+	default:
+		b.err = b.decodeError(fmt.Errorf("%w: %q", ErrUnknownBlockMagic, h.Magic))
+		return b.err
+	}
+	if expectedSize <= 0 {
+		b.err = b.decodeError(fmt.Errorf("%w: %d", ErrInvalidTargetSize, expectedSize))
+		return b.err
+	}
+
+	// Some legacy writers include the uncompressed size in the block payload;
+	// current files always do. Peek at it the way decompressBlock does.
+	if h.Size >= 8 {
+		if err := b.fill(b.ahead[:]); err != nil {
+			b.err = err
+			return err
+		}
+		peek := int(binary.LittleEndian.Uint32(b.ahead[:4]))
+		c0 := int(b.ahead[4]) | (int(b.ahead[5]) << 8) | (int(b.ahead[6]) << 16)
+		if peek == expectedSize && c0 > 0 && c0 < (1<<20) {
+			b.head = b.ahead[4:]
+		} else {
+			b.head = b.ahead[:]
+		}
+	}
+
+	const dictCap = 64 * 1024
+	if cap(b.dict) < dictCap {
+		b.dict = make([]byte, dictCap)
+	}
+	b.dict = b.dict[:dictCap]
+	b.out = ensureLen(b.out, ChunkSize)
+
+	return nil
+}
+
+// Read implements io.Reader over the decompressed payload.
+func (b *blockReader) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.magic == BlockMagicCOPY {
+		return b.readCopy(p)
+	}
+
+	for len(b.pending) == 0 {
+		if b.last {
+			b.err = b.finish()
+			return 0, b.err
+		}
+		if err := b.readChunk(); err != nil {
+			b.err = err
+			return 0, err
+		}
+	}
+
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+// readPayload reads the whole payload into dst, reusing its capacity.
+// It fails unless the body decodes to exactly expectedSize bytes.
+func (b *blockReader) readPayload(dst []byte, src io.Reader, index int, h blockHeader, expectedSize int) ([]byte, error) {
+	if err := b.reset(src, index, h, expectedSize); err != nil {
+		return nil, err
+	}
+
+	payload := ensureLen(dst, expectedSize)
+	if _, err := io.ReadFull(b, payload); err != nil {
+		return nil, err
+	}
+	var probe [1]byte
+	if n, err := b.Read(probe[:]); n != 0 || err != io.EOF {
+		if err == nil {
+			err = b.decodeError(ErrDecodeOverrun)
+		}
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// readCopy serves a COPY body straight from src.
+func (b *blockReader) readCopy(p []byte) (int, error) {
+	if b.remaining == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	if err := b.fill(p); err != nil {
+		b.err = err
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// readChunk inflates the next LZ4 chunk into b.pending.
+func (b *blockReader) readChunk() error {
+	var header [4]byte
+	if left := b.left(); left < 4 {
+		return b.decodeError(fmt.Errorf("%w: need 4 bytes header, have %d", ErrChunkStreamTruncated, left))
+	}
+	if err := b.readBody(header[:]); err != nil {
+		return err
+	}
+
+	cSize := int(header[0]) | (int(header[1]) << 8) | (int(header[2]) << 16)
+	flags := header[3]
+	if (flags &^ 0x80) != 0 {
+		return b.decodeError(fmt.Errorf("%w: 0x%02x", ErrUnknownLZ4Flags, flags))
+	}
+	remainingData := b.left()
+	if cSize <= 0 || int64(cSize) > remainingData {
+		return b.decodeError(fmt.Errorf("%w: %d (remaining %d)", ErrInvalidChunkSize, cSize, remainingData))
+	}
+
+	b.chunk = ensureLen(b.chunk, cSize)
+	if err := b.readBody(b.chunk); err != nil {
+		return err
+	}
+
+	remaining := b.target - b.produced
+	if remaining <= 0 {
+		return b.decodeError(ErrDecodeOverrun)
+	}
+	want := min(ChunkSize, remaining)
+	n, err := lz4.UncompressBlockWithDict(b.chunk, b.out[:want], b.dict[:b.dictSize])
+	if err != nil {
+		return b.decodeError(fmt.Errorf("%w: %v", ErrLZ4Decode, err))
+	}
+
+	b.produced += n
+	b.pending = b.out[:n]
+	b.dictSize = pushDict(b.dict, b.dictSize, b.pending)
+	b.last = (flags & 0x80) != 0
+	return nil
+}
+
+// finish checks the decoded size and that the last chunk ended the body.
+func (b *blockReader) finish() error {
+	if b.produced != b.target {
+		return b.decodeError(fmt.Errorf("%w: expected %d, got %d", ErrDecodedSizeMismatch, b.target, b.produced))
+	}
+	if left := b.left(); left != 0 {
+		return b.decodeError(fmt.Errorf("%w: %d bytes left after decode", ErrBlockLengthMismatch, left))
+	}
+
+	return io.EOF
+}
+
+// readBody reads len(p) body bytes, first from the read-ahead head, then from src.
+func (b *blockReader) readBody(p []byte) error {
+	n := copy(p, b.head)
+	b.head = b.head[n:]
+	if n == len(p) {
+		return nil
+	}
+
+	return b.fill(p[n:])
+}
+
+// left returns the body bytes not yet parsed.
+func (b *blockReader) left() int64 {
+	return b.remaining + int64(len(b.head))
+}
+
+// fill reads exactly len(p) bytes from src. A body that ends early fails
+// like a whole-body io.ReadFull would: io.EOF before its first byte,
+// io.ErrUnexpectedEOF after it.
+func (b *blockReader) fill(p []byte) error {
+	n, err := io.ReadFull(b.src, p)
+	b.remaining -= int64(n)
+	if n > 0 {
+		b.consumed = true
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) && b.consumed {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("%w: mipmap %d: %v: %s: %v", ErrReadBlockBody, b.index, ErrBlockBodyRead, b.magic, err)
+	}
+
+	return nil
+}
+
+// decodeError wraps an invalid body error as decompressBlock callers do.
+func (b *blockReader) decodeError(err error) error {
+	return fmt.Errorf("%w: mipmap %d: %v", ErrDecompressBlock, b.index, err)
+}
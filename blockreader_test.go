@@ -0,0 +1,126 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// storedBlock compresses raw and returns its table entry and body as written to a file.
+func storedBlock(t *testing.T, raw []byte, mode CompressionMode) (blockHeader, []byte) {
+	t.Helper()
+
+	compression, err := normalizeCompressionOptions(CompressionOptions{Mode: mode}, true)
+	if err != nil {
+		t.Fatalf("normalizeCompressionOptions: %v", err)
+	}
+	var compressor blockCompressor
+	block, _, err := compressor.compressBlock(nil, raw, compression)
+	if err != nil {
+		t.Fatalf("compressBlock: %v", err)
+	}
+	var body bytes.Buffer
+	if err := writeBlockData(&body, block); err != nil {
+		t.Fatalf("writeBlockData: %v", err)
+	}
+
+	return blockHeader{Magic: block.Magic, Size: int32(body.Len())}, body.Bytes() //nolint:gosec // test data is small
+}
+
+// blockTestData returns n bytes that compress well in every chunk.
+func blockTestData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i/1024) ^ byte(i%16)
+	}
+
+	return data
+}
+
+func TestBlockReaderMatchesDecompressBlock(t *testing.T) {
+	t.Parallel()
+
+	raw := blockTestData(5*ChunkSize + 123)
+	for _, mode := range []CompressionMode{CompressionNone, CompressionLZ4, CompressionLZ4HC} {
+		h, body := storedBlock(t, raw, mode)
+		if mode != CompressionNone && h.Magic != BlockMagicLZ4 {
+			t.Fatalf("%s: stored %q block, want LZ4", mode, h.Magic)
+		}
+
+		// One-byte source reads and odd destination reads cross every chunk boundary.
+		var b blockReader
+		if err := b.reset(iotest.OneByteReader(bytes.NewReader(body)), 3, h, len(raw)); err != nil {
+			t.Fatalf("%s: reset: %v", mode, err)
+		}
+		got, err := io.ReadAll(iotest.HalfReader(&b))
+		if err != nil {
+			t.Fatalf("%s: read: %v", mode, err)
+		}
+		if !bytes.Equal(got, raw) {
+			t.Fatalf("%s: streamed payload differs", mode)
+		}
+
+		// Reuse keeps the buffers and reads the next body from the same stream position.
+		stream := bytes.NewReader(append(bytes.Clone(body), body...))
+		for range 2 {
+			payload, err := b.readPayload(got, stream, 3, h, len(raw))
+			if err != nil || !bytes.Equal(payload, raw) {
+				t.Fatalf("%s: readPayload: %v", mode, err)
+			}
+		}
+	}
+}
+
+func TestBlockReaderErrors(t *testing.T) {
+	t.Parallel()
+
+	raw := blockTestData(3 * ChunkSize)
+	h, body := storedBlock(t, raw, CompressionLZ4)
+
+	corrupt := func(edit func([]byte) []byte) []byte {
+		return edit(bytes.Clone(body))
+	}
+	tests := []struct {
+		name   string
+		body   []byte
+		target error
+	}{
+		{"flags", corrupt(func(b []byte) []byte { b[7] = 0x40; return b }), ErrDecompressBlock},
+		{"chunk size", corrupt(func(b []byte) []byte { b[4], b[5], b[6] = 0xff, 0xff, 0x7f; return b }), ErrDecompressBlock},
+		{"trailing", append(bytes.Clone(body), 0, 0), ErrDecompressBlock},
+		{"truncated", body[:len(body)/2], ErrReadBlockBody},
+	}
+	for _, tc := range tests {
+		size := h.Size
+		if tc.name == "trailing" {
+			size += 2
+		}
+		_, streamErr := new(blockReader).readPayload(nil, bytes.NewReader(tc.body), 1, blockHeader{Magic: BlockMagicLZ4, Size: size}, len(raw))
+		if !errors.Is(streamErr, tc.target) {
+			t.Fatalf("%s: error = %v, want %v", tc.name, streamErr, tc.target)
+		}
+		if tc.target != ErrDecompressBlock {
+			continue
+		}
+
+		// Invalid bodies fail with the same cause as the in-memory decompressor.
+		_, memErr := decompressBlock(&Block{Magic: BlockMagicLZ4, Size: size, Data: tc.body}, len(raw))
+		if memErr == nil || !strings.HasSuffix(streamErr.Error(), memErr.Error()) {
+			t.Fatalf("%s: error = %v, want cause %v", tc.name, streamErr, memErr)
+		}
+	}
+
+	// Payload bytes are served before the rest of the body arrives.
+	firstChunk := 8 + (int(body[4]) | int(body[5])<<8 | int(body[6])<<16)
+	var b blockReader
+	if err := b.reset(iotest.DataErrReader(bytes.NewReader(body[:firstChunk])), 1, h, len(raw)); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	first := make([]byte, 16)
+	if _, err := io.ReadFull(&b, first); err != nil || !bytes.Equal(first, raw[:16]) {
+		t.Fatalf("first bytes = %v, err %v", first, err)
+	}
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG input
+	_ "image/png"  // register PNG input
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+	"github.com/woozymasta/edds"
+)
+
+// runConvert implements "edds convert".
+// A directory input converts every PNG/JPEG below it into a mirrored output tree.
+func runConvert(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", "[flags] input.png output.edds | input_dir output_dir", stderr)
+	format := fs.String("format", "BGRA8", "output format (BGRA8, RGBA8, DXT1, DXT5, BC4, BC5, BC7, BC6HU, RGBA16F, ...)")
+	mips := fs.Int("mips", 0, "maximum mip levels to write (0 = full chain)")
+	compression := fs.String("compression", "LZ4", "block compression: None, LZ4 or LZ4HC")
+	hcLevel := fs.Int("hc-level", 0, "LZ4HC level 1..9 (0 = library default)")
+	minRatio := fs.Float64("min-ratio", 0, "minimum raw/stored ratio to keep a compressed block (0 = default)")
+	swizzle := fs.String("swizzle", "None", "Workbench swizzle profile (for example NormalMapGA)")
+	colorSpace := fs.String("color-space", "unspecified", "declared color space: unspecified, linear or sRGB")
+	mipFilter := fs.String("mip-filter", "Box", "mipmap filter: Box, Triangle, Kaiser or Lanczos")
+	linearMips := fs.Bool("linear-mips", false, "filter sRGB color mipmaps in linear light")
+	normalMap := fs.String("normal-map", "None", "normal map mipmaps: None, XY (RG holds XY) or XYZ (RGB holds XYZ)")
+	toksvig := fs.Float64("toksvig-power", 0, "specular power stored as alpha 255 for Toksvig gloss adjustment (0 = off)")
+	alphaCoverage := fs.Uint("alpha-coverage", 0, "alpha-test reference 1..255 whose coverage mipmaps preserve (0 = off)")
+	quality := fs.Int("quality", 0, "BCn encoder quality level (0 = encoder default)")
+	workers := fs.Int("workers", 0, "BCn encoder workers (0 = GOMAXPROCS)")
+	concurrency := fs.Int("concurrency", 0, "mip levels encoded and compressed in parallel (0 = serial)")
+	jobs := fs.Int("jobs", 0, "files converted in parallel in directory mode (0 = GOMAXPROCS)")
+	manifest := fs.String("manifest", "", "incremental manifest for directory mode: skip unchanged inputs, delete stale outputs")
+	if err := parseArgs(fs, args, 2); err != nil {
+		return err
+	}
+
+	opts, err := convertOptions(*format, *compression, *swizzle, *colorSpace, *mipFilter, *normalMap)
+	if err != nil {
+		return err
+	}
+	opts.MaxMipMaps = *mips
+	opts.LinearMips = *linearMips
+	opts.Concurrency = *concurrency
+	opts.ToksvigPower = float32(*toksvig)
+	if *alphaCoverage > 255 {
+		return fmt.Errorf("alpha-coverage %d out of range 0..255", *alphaCoverage)
+	}
+	opts.AlphaCoverageRef = uint8(*alphaCoverage) //nolint:gosec // range checked above
+	opts.Compression.HCLevel = *hcLevel
+	opts.Compression.MinRatio = *minRatio
+	opts.EncodeOptions = &bcn.EncodeOptions{QualityLevel: *quality, Workers: *workers}
+
+	src, dst := fs.Arg(0), fs.Arg(1)
+	if stat, err := os.Stat(src); err == nil && stat.IsDir() {
+		return convertDir(src, dst, opts, *jobs, *manifest, stdout, stderr)
+	}
+
+	img, err := decodeImageFile(src)
+	if err != nil {
+		return err
+	}
+
+	return edds.WriteWithOptions(img, dst, opts)
+}
+
+// convertDir converts a directory tree, printing written files to stdout
+// and failures to stderr. It fails after the run if any file failed.
+func convertDir(src, dst string, opts *edds.WriteOptions, jobs int, manifest string, stdout, stderr io.Writer) error {
+	out := &errWriter{w: stdout}
+	report, err := edds.ConvertDir(context.Background(), src, dst, &edds.BatchOptions{
+		WriteOptions: opts,
+		Manifest:     manifest,
+		Workers:      jobs,
+		Progress: func(r edds.BatchResult) {
+			if r.Err != nil {
+				_, _ = fmt.Fprintf(stderr, "[%d/%d] %s: %v\n", r.Done, r.Total, r.Source, r.Err)
+				return
+			}
+			if r.Skipped {
+				out.printf("[%d/%d] %s (up to date)\n", r.Done, r.Total, r.Output)
+				return
+			}
+			out.printf("[%d/%d] %s\n", r.Done, r.Total, r.Output)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, removed := range report.Removed {
+		out.printf("removed %s\n", removed)
+	}
+	for _, failed := range report.Failed {
+		if errors.Is(failed.Err, edds.ErrRemoveStaleOutput) {
+			_, _ = fmt.Fprintln(stderr, failed.Err)
+		}
+	}
+	if out.err != nil {
+		return out.err
+	}
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d of %d files failed", len(report.Failed), report.Total)
+	}
+
+	return nil
+}
+
+// convertOptions parses the named write settings.
+func convertOptions(format, compression, swizzle, colorSpace, mipFilter, normalMap string) (*edds.WriteOptions, error) {
+	f, err := edds.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := edds.ParseCompressionMode(compression)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := edds.ParseSwizzleProfile(swizzle)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := edds.ParseColorSpace(colorSpace)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := edds.ParseMipFilter(mipFilter)
+	if err != nil {
+		return nil, err
+	}
+	normal, err := edds.ParseNormalMapMode(normalMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &edds.WriteOptions{
+		Format:         f,
+		Compression:    edds.CompressionOptions{Mode: mode},
+		SwizzleProfile: profile,
+		ColorSpace:     cs,
+		MipFilter:      filter,
+		NormalMap:      normal,
+	}, nil
+}
+
+// decodeImageFile decodes any image format registered with the image package.
+func decodeImageFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return img, nil
+}
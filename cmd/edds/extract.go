@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/woozymasta/edds"
+)
+
+// runExtract implements "edds extract".
+func runExtract(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("extract", "[-level N | -all] [-raw] input.edds output.png", stderr)
+	level := fs.Int("level", 0, "mip level to extract (0 = largest)")
+	all := fs.Bool("all", false, "extract every level as output_mipN.png")
+	linearize := fs.Bool("linearize", false, "convert sRGB textures to linear")
+	raw := fs.Bool("raw", false, "write the decompressed payload in its stored format instead of PNG")
+	if err := parseArgs(fs, args, 2); err != nil {
+		return err
+	}
+
+	src, dst := fs.Arg(0), fs.Arg(1)
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	stat, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	opts := &edds.ReadOptions{Linearize: *linearize}
+	f, err := edds.OpenWithOptions(in, stat.Size(), opts)
+	if errors.Is(err, edds.ErrUnsupportedTextureType) {
+		if *raw {
+			return fmt.Errorf("%s: %w; -raw extracts only 2D textures", src, err)
+		}
+		return extractSurfaces(in, src, err, opts, *level, *all, dst, stdout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	if !*all {
+		return extractLevel(f, *level, dst, *raw)
+	}
+
+	ext := filepath.Ext(dst)
+	base := strings.TrimSuffix(dst, ext)
+	for level := range f.NumLevels() {
+		path := fmt.Sprintf("%s_mip%d%s", base, level, ext)
+		if err := extractLevel(f, level, path, *raw); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(stdout, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractLevel decodes one mip level and writes it as PNG.
+// HDR textures are clamped to 0..1 and written as 16-bit PNG.
+// With raw set the decompressed payload is streamed to path unchanged instead.
+func extractLevel(f *edds.File, level int, path string, raw bool) error {
+	if raw {
+		payload, err := f.PayloadReader(level)
+		if err != nil {
+			return fmt.Errorf("level %d: %w", level, err)
+		}
+		if err := writeFile(path, func(w io.Writer) error {
+			_, err := io.Copy(w, payload)
+			return err
+		}); err != nil {
+			return fmt.Errorf("level %d: %w", level, err)
+		}
+		return nil
+	}
+
+	img, err := f.Image(level)
+	if err != nil {
+		return fmt.Errorf("level %d: %w", level, err)
+	}
+
+	return writePNG(path, img)
+}
+
+// extractSurfaces writes every surface of a cubemap, texture array or volume
+// texture as its own PNG, named output_faceN, output_sliceN or output_depthN,
+// with a _mipN suffix under -all. Each written path is printed.
+// The whole texture is decoded first. openErr, which names the layout,
+// is returned for layouts none of the decoders accept, such as cubemap arrays.
+func extractSurfaces(
+	in io.ReadSeeker,
+	src string,
+	openErr error,
+	opts *edds.ReadOptions,
+	level int,
+	all bool,
+	dst string,
+	stdout io.Writer,
+) error {
+	levels, label, err := decodeSurfaces(in, opts)
+	if errors.Is(err, edds.ErrUnsupportedTextureType) {
+		return fmt.Errorf("%s: %w", src, openErr)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	first, last := level, level
+	if all {
+		first, last = 0, len(levels)-1
+	} else if level < 0 || level >= len(levels) {
+		return fmt.Errorf("%w: level %d, mipmaps %d", edds.ErrMipLevelOutOfRange, level, len(levels))
+	}
+
+	ext := filepath.Ext(dst)
+	base := strings.TrimSuffix(dst, ext)
+	for level := first; level <= last; level++ {
+		for surface, img := range levels[level] {
+			path := fmt.Sprintf("%s_%s%d%s", base, label, surface, ext)
+			if all {
+				path = fmt.Sprintf("%s_%s%d_mip%d%s", base, label, surface, level, ext)
+			}
+			if err := writePNG(path, img); err != nil {
+				return fmt.Errorf("level %d %s %d: %w", level, label, surface, err)
+			}
+			if _, err := fmt.Fprintln(stdout, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeSurfaces decodes in as a cubemap, texture array or volume texture
+// and returns its images by level and surface, with the surface label.
+// Each decoder rejects other layouts from the headers, before reading blocks.
+func decodeSurfaces(in io.ReadSeeker, opts *edds.ReadOptions) ([][]image.Image, string, error) {
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+	cube, err := edds.DecodeCube(in, opts)
+	if err == nil {
+		levels := make([][]image.Image, len(cube.Faces[0]))
+		for level := range levels {
+			for _, mips := range cube.Faces {
+				levels[level] = append(levels[level], mips[level])
+			}
+		}
+		return levels, "face", nil
+	}
+	if !errors.Is(err, edds.ErrUnsupportedTextureType) {
+		return nil, "", err
+	}
+
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+	array, err := edds.DecodeArray(in, opts)
+	if err == nil {
+		levels := make([][]image.Image, len(array.Slices[0]))
+		for level := range levels {
+			for _, mips := range array.Slices {
+				levels[level] = append(levels[level], mips[level])
+			}
+		}
+		return levels, "slice", nil
+	}
+	if !errors.Is(err, edds.ErrUnsupportedTextureType) {
+		return nil, "", err
+	}
+
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+	volume, err := edds.DecodeVolume(in, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return volume.Levels, "depth", nil
+}
+
+// writePNG encodes img into the file at path.
+func writePNG(path string, img image.Image) error {
+	return writeFile(path, func(w io.Writer) error {
+		return png.Encode(w, img)
+	})
+}
+
+// writeFile fills a sibling temporary file with write and renames it to path,
+// so a failed write never leaves a truncated output behind.
+func writeFile(path string, write func(io.Writer) error) (err error) {
+	out, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(out.Name())
+		}
+	}()
+
+	// CreateTemp uses 0600; match the permissions os.Create would give.
+	if err := out.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := write(out); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(out.Name(), path)
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/woozymasta/edds"
+)
+
+// infoReport is the JSON form of edds.Info.
+type infoReport struct {
+	DX10        *dx10Report   `json:"dx10,omitempty"`
+	Path        string        `json:"path"`
+	Format      string        `json:"format"`
+	ColorSpace  string        `json:"color_space"`
+	FourCC      string        `json:"fourcc,omitempty"`
+	Blocks      []blockReport `json:"blocks"`
+	Size        int64         `json:"size"`
+	DataOffset  int64         `json:"data_offset"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	Depth       uint32        `json:"depth"`
+	MipMapCount int           `json:"mip_map_count"`
+	Flags       uint32        `json:"flags"`
+	Caps        uint32        `json:"caps"`
+	Caps2       uint32        `json:"caps2"`
+	Legacy      bool          `json:"legacy"`
+	Enfusion    bool          `json:"enfusion"`
+}
+
+// dx10Report is the JSON form of the DX10 header.
+type dx10Report struct {
+	DXGIFormat        uint32 `json:"dxgi_format"`
+	ResourceDimension uint32 `json:"resource_dimension"`
+	MiscFlag          uint32 `json:"misc_flag"`
+	ArraySize         uint32 `json:"array_size"`
+}
+
+// blockReport is the JSON form of edds.BlockInfo.
+type blockReport struct {
+	Magic            string `json:"magic"`
+	Offset           int64  `json:"offset"`
+	Index            int    `json:"index"`
+	Level            int    `json:"level"`
+	Width            int    `json:"width"`
+	Height           int    `json:"height"`
+	Size             int32  `json:"size"`
+	UncompressedSize int32  `json:"uncompressed_size"`
+}
+
+// runInfo implements "edds info".
+func runInfo(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("info", "[-json] file.edds", stderr)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if err := parseArgs(fs, args, 1); err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := edds.Inspect(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	report := newInfoReport(path, info)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	return printInfo(stdout, report)
+}
+
+// newInfoReport converts info into its printable form.
+func newInfoReport(path string, info *edds.Info) infoReport {
+	report := infoReport{
+		Path:        path,
+		Format:      edds.FormatName(info.Format),
+		ColorSpace:  info.ColorSpace.String(),
+		FourCC:      info.FourCC,
+		Blocks:      make([]blockReport, len(info.Blocks)),
+		Size:        info.Size,
+		DataOffset:  info.DataOffset,
+		Width:       info.Width,
+		Height:      info.Height,
+		Depth:       info.Header.Depth,
+		MipMapCount: info.MipMapCount,
+		Flags:       info.Header.Flags,
+		Caps:        info.Header.Caps,
+		Caps2:       info.Header.Caps2,
+		Legacy:      info.Legacy,
+		Enfusion:    info.Enfusion,
+	}
+	if info.DX10 != nil {
+		report.DX10 = &dx10Report{
+			DXGIFormat:        info.DX10.DXGIFormat,
+			ResourceDimension: info.DX10.ResourceDimension,
+			MiscFlag:          info.DX10.MiscFlag,
+			ArraySize:         info.DX10.ArraySize,
+		}
+	}
+	for i, block := range info.Blocks {
+		report.Blocks[i] = blockReport(block)
+	}
+
+	return report
+}
+
+// printInfo writes report as aligned text.
+func printInfo(w io.Writer, r infoReport) error {
+	p := &errWriter{w: w}
+	p.printf("File:         %s (%d bytes)\n", r.Path, r.Size)
+	p.printf("Size:         %dx%d", r.Width, r.Height)
+	if r.Depth > 1 {
+		p.printf("x%d", r.Depth)
+	}
+	p.printf("\n")
+	p.printf("Format:       %s (color space %s)\n", r.Format, r.ColorSpace)
+	if r.FourCC != "" {
+		p.printf("FourCC:       %q\n", r.FourCC)
+	}
+	if r.DX10 != nil {
+		p.printf("DX10:         DXGI %d, dimension %d, array size %d, misc 0x%x\n",
+			r.DX10.DXGIFormat, r.DX10.ResourceDimension, r.DX10.ArraySize, r.DX10.MiscFlag)
+	}
+	p.printf("Flags:        0x%08x caps 0x%08x caps2 0x%08x\n", r.Flags, r.Caps, r.Caps2)
+	p.printf("Mipmaps:      %d\n", r.MipMapCount)
+	p.printf("Enfusion:     %t\n", r.Enfusion)
+	p.printf("Data offset:  %d\n", r.DataOffset)
+	if r.Legacy {
+		p.printf("Layout:       legacy single block\n")
+		return p.err
+	}
+
+	p.printf("\n%5s %5s %11s %5s %10s %10s %10s\n", "index", "level", "size", "magic", "stored", "raw", "offset")
+	for _, b := range r.Blocks {
+		p.printf("%5d %5d %11s %5s %10d %10d %10d\n",
+			b.Index, b.Level, fmt.Sprintf("%dx%d", b.Width, b.Height), b.Magic, b.Size, b.UncompressedSize, b.Offset)
+	}
+
+	return p.err
+}
+
+// errWriter keeps the first write error so formatted output needs no per-line checks.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+// printf writes formatted output unless an earlier write failed.
+func (p *errWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+// Command edds inspects, converts, and extracts EDDS textures.
+//
+// Usage:
+//
+//	edds info [-json] file.edds
+//	edds convert [flags] input.png output.edds
+//	edds convert [flags] input_dir output_dir
+//	edds extract [-level N | -all] [-raw] input.edds output.png
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// errUsage reports invalid command-line arguments; the usage text is already printed.
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		_, _ = fmt.Fprintln(os.Stderr, "edds:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches args to a subcommand.
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return errUsage
+	}
+
+	switch args[0] {
+	case "info":
+		return runInfo(args[1:], stdout, stderr)
+	case "convert":
+		return runConvert(args[1:], stdout, stderr)
+	case "extract":
+		return runExtract(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return nil
+	default:
+		_, _ = fmt.Fprintf(stderr, "edds: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return errUsage
+	}
+}
+
+// printUsage prints the command overview.
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprint(w, `Usage: edds <command> [flags] [arguments]
+
+Commands:
+  info      print headers, format, mip levels and block table
+  convert   encode a PNG/JPEG image or directory tree into EDDS
+  extract   decode EDDS mip levels into PNG
+
+Run "edds <command> -h" for command flags.
+`)
+}
+
+// newFlagSet returns a flag set that reports errors to stderr with a usage line.
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: edds %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseArgs parses flags and checks the number of positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, positional int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != positional {
+		fs.Usage()
+		return errUsage
+	}
+
+	return nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/edds"
+)
+
+func writeTestPNG(t *testing.T, path string) {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 16), G: uint8(y * 32), B: 40, A: 255}) //nolint:gosec // bounded
+		}
+	}
+	if err := writePNG(path, img); err != nil {
+		t.Fatalf("writePNG: %v", err)
+	}
+}
+
+func TestConvertInfoExtract(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.png")
+	dst := filepath.Join(dir, "out.edds")
+	writeTestPNG(t, src)
+
+	var stderr bytes.Buffer
+	err := run([]string{"convert", "-format", "dxt5", "-mips", "3", "-compression", "lz4hc", "-hc-level", "9",
+		"-swizzle", "normalspecularmapxyzs", "-color-space", "srgb", "-mip-filter", "kaiser", "-linear-mips", "-alpha-coverage", "128",
+		"-normal-map", "xy", "-toksvig-power", "32", src, dst}, &bytes.Buffer{}, &stderr)
+	if err != nil {
+		t.Fatalf("convert: %v (%s)", err, stderr.String())
+	}
+
+	var stdout bytes.Buffer
+	if err := run([]string{"info", "-json", dst}, &stdout, &stderr); err != nil {
+		t.Fatalf("info: %v", err)
+	}
+	var report infoReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("info JSON: %v", err)
+	}
+	if report.Format != "BC3" || report.ColorSpace != "sRGB" || report.MipMapCount != 3 ||
+		len(report.Blocks) != 3 || report.DX10 == nil || report.DX10.DXGIFormat != 78 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"info", dst}, &stdout, &stderr); err != nil {
+		t.Fatalf("info text: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Format:       BC3 (color space sRGB)") {
+		t.Fatalf("info text:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"extract", "-all", dst, filepath.Join(dir, "out.png")}, &stdout, &stderr); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	for level, size := range []image.Point{{16, 8}, {8, 4}, {4, 2}} {
+		f, err := os.Open(filepath.Join(dir, fmt.Sprintf("out_mip%d.png", level)))
+		if err != nil {
+			t.Fatalf("open level %d: %v", level, err)
+		}
+		cfg, err := png.DecodeConfig(f)
+		_ = f.Close()
+		if err != nil || cfg.Width != size.X || cfg.Height != size.Y {
+			t.Fatalf("level %d config %+v, err %v", level, cfg, err)
+		}
+	}
+
+	rawPath := filepath.Join(dir, "level0.bc3")
+	if err := run([]string{"extract", "-raw", dst, rawPath}, &stdout, &stderr); err != nil {
+		t.Fatalf("extract raw: %v", err)
+	}
+	raw, err := os.ReadFile(rawPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloads, err := edds.ReadPayloads(dst, nil)
+	if err != nil {
+		t.Fatalf("ReadPayloads: %v", err)
+	}
+	if !bytes.Equal(raw, payloads.Mipmaps[0]) {
+		t.Fatalf("raw level 0 has %d bytes, want the %d stored payload bytes", len(raw), len(payloads.Mipmaps[0]))
+	}
+}
+
+func TestExtractFailureLeavesNoOutput(t *testing.T) {
+	t.Parallel()
+
+	// A flat image keeps the largest block LZ4, whose body is then corrupted.
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	var buf bytes.Buffer
+	if err := edds.EncodeWithOptions(&buf, img, &edds.WriteOptions{MaxMipMaps: 1, Compression: edds.CompressionOptions{Mode: edds.CompressionLZ4}}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	data := buf.Bytes()
+	info, err := edds.Inspect(bytes.NewReader(data))
+	if err != nil || info.Blocks[0].Magic != edds.BlockMagicLZ4 {
+		t.Fatalf("Inspect = %+v, err %v", info, err)
+	}
+	body := info.Blocks[0]
+	for i := body.Offset + 8; i < body.Offset+int64(body.Size); i++ {
+		data[i] = 0xff
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "bad.edds")
+	if err := os.WriteFile(src, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	dst := filepath.Join(dir, "level0.bin")
+	if err := run([]string{"extract", "-raw", src, dst}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Fatal("extract -raw of a corrupt block succeeded")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("extract left %d files, want only the input", len(entries))
+	}
+}
+
+func TestExtractCubemapFaces(t *testing.T) {
+	t.Parallel()
+
+	var cube edds.CubeTexture
+	for face := range cube.Faces {
+		img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+		for i := range img.Pix {
+			img.Pix[i] = uint8(40 * (face + 1)) //nolint:gosec // bounded
+		}
+		cube.Faces[face] = []image.Image{img}
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "sky.edds")
+	if err := edds.WriteCube(&cube, src, &edds.WriteOptions{MaxMipMaps: 2}); err != nil {
+		t.Fatalf("WriteCube: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := run([]string{"extract", "-all", src, filepath.Join(dir, "sky.png")}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("extract -all: %v", err)
+	}
+	if paths := strings.Fields(stdout.String()); len(paths) != 12 {
+		t.Fatalf("extract -all wrote %d files, want 6 faces x 2 levels", len(paths))
+	}
+	f, err := os.Open(filepath.Join(dir, "sky_face3_mip1.png"))
+	if err != nil {
+		t.Fatalf("Open face 3 level 1: %v", err)
+	}
+	img, err := png.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+	if got := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); got.R != 160 || img.Bounds().Dx() != 4 {
+		t.Fatalf("face 3 level 1 = %v at %v, want R 160 at 4x4", got, img.Bounds())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"extract", "-level", "1", src, filepath.Join(dir, "one.png")}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("extract -level 1: %v", err)
+	}
+	if paths := strings.Fields(stdout.String()); len(paths) != 6 || paths[0] != filepath.Join(dir, "one_face0.png") {
+		t.Fatalf("extract -level 1 wrote %v", paths)
+	}
+
+	err = run([]string{"extract", "-raw", src, filepath.Join(dir, "raw.bin")}, &bytes.Buffer{}, &bytes.Buffer{})
+	if !errors.Is(err, edds.ErrUnsupportedTextureType) || !strings.Contains(err.Error(), "2D") {
+		t.Fatalf("extract -raw error = %v, want a 2D-only ErrUnsupportedTextureType", err)
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := [][]string{
+		nil,
+		{"unknown"},
+		{"info"},
+		{"extract", "only-one.edds"},
+	}
+	for _, args := range tests {
+		if err := run(args, &bytes.Buffer{}, &bytes.Buffer{}); !errors.Is(err, errUsage) {
+			t.Fatalf("run(%q) = %v, want errUsage", args, err)
+		}
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "in.png")
+	writeTestPNG(t, src)
+	err := run([]string{"convert", "-format", "nope", src, filepath.Join(dir, "out.edds")}, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Fatalf("bad format error = %v", err)
+	}
+}
+
+func TestConvertDirectory(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	dst := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o750); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	writeTestPNG(t, filepath.Join(src, "a.png"))
+	writeTestPNG(t, filepath.Join(src, "sub", "b.png"))
+	if err := os.WriteFile(filepath.Join(src, "sub", "bad.png"), []byte("junk"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"convert", "-jobs", "2", src, dst}, &stdout, &stderr)
+	if err == nil || err.Error() != "1 of 3 files failed" {
+		t.Fatalf("convert dir error = %v", err)
+	}
+	if !strings.Contains(stderr.String(), "sub/bad.png") || strings.Count(stdout.String(), "\n") != 2 {
+		t.Fatalf("stdout:\n%s\nstderr:\n%s", stdout.String(), stderr.String())
+	}
+	for _, name := range []string{"a.edds", filepath.Join("sub", "b.edds")} {
+		if _, err := os.Stat(filepath.Join(dst, name)); err != nil {
+			t.Fatalf("output %s: %v", name, err)
+		}
+	}
+}
+
+func TestConvertDirectoryManifest(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	dst := t.TempDir()
+	manifest := filepath.Join(dst, "manifest.json")
+	writeTestPNG(t, filepath.Join(src, "a.png"))
+	writeTestPNG(t, filepath.Join(src, "b.png"))
+
+	var stdout bytes.Buffer
+	if err := run([]string{"convert", "-manifest", manifest, src, dst}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(src, "b.png")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	stdout.Reset()
+	if err := run([]string{"convert", "-manifest", manifest, src, dst}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "a.edds (up to date)") || !strings.Contains(out, "removed "+filepath.Join(dst, "b.edds")) {
+		t.Fatalf("stdout:\n%s", out)
+	}
+}
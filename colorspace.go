@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/woozymasta/bcn"
+)
+
+// ColorSpace is the color encoding declared for stored texels.
+type ColorSpace int
+
+const (
+	// ColorSpaceUnspecified means the headers do not declare a color space.
+	// Legacy FourCC and mask-based headers, which Workbench writes, carry no such information.
+	ColorSpaceUnspecified ColorSpace = iota
+	// ColorSpaceLinear is a DX10 UNORM, SNORM or float format.
+	ColorSpaceLinear
+	// ColorSpaceSRGB is a DX10 *_UNORM_SRGB format.
+	ColorSpaceSRGB
+)
+
+// String returns the color space name.
+func (c ColorSpace) String() string {
+	switch c {
+	case ColorSpaceUnspecified:
+		return "unspecified"
+	case ColorSpaceLinear:
+		return "linear"
+	case ColorSpaceSRGB:
+		return "sRGB"
+	default:
+		return fmt.Sprintf("ColorSpace(%d)", int(c))
+	}
+}
+
+// ParseColorSpace returns the color space with the given name
+// ("unspecified", "linear" or "sRGB"), ignoring case.
+func ParseColorSpace(name string) (ColorSpace, error) {
+	for _, c := range []ColorSpace{ColorSpaceUnspecified, ColorSpaceLinear, ColorSpaceSRGB} {
+		if strings.EqualFold(name, c.String()) {
+			return c, nil
+		}
+	}
+
+	return ColorSpaceUnspecified, fmt.Errorf("%w: %q", ErrInvalidColorSpace, name)
+}
+
+// detectColorSpace reports the color space declared by the DX10 header.
+func detectColorSpace(dx10 *bcn.DDSHeaderDX10) ColorSpace {
+	if dx10 == nil {
+		return ColorSpaceUnspecified
+	}
+
+	switch dx10.DXGIFormat {
+	case 29, 72, 75, 78, 91, 93, 99:
+		return ColorSpaceSRGB
+	default:
+		return ColorSpaceLinear
+	}
+}
+
+// srgbDxgiFormat maps a format to its DXGI *_UNORM_SRGB code.
+func srgbDxgiFormat(format bcn.Format) (uint32, error) {
+	switch format {
+	case bcn.FormatDXT1:
+		return 72, nil
+	case bcn.FormatDXT3:
+		return 75, nil
+	case bcn.FormatDXT5:
+		return 78, nil
+	case bcn.FormatBC7:
+		return 99, nil
+	case bcn.FormatRGBA8:
+		return 29, nil
+	case bcn.FormatBGRA8:
+		return 91, nil
+	case bcn.FormatBGRX8:
+		return 93, nil
+	default:
+		return 0, fmt.Errorf("%w: %s has no sRGB variant", ErrInvalidColorSpace, FormatName(format))
+	}
+}
+
+// linearizes reports whether opts request linear output for a stream with the given DX10 header.
+func linearizes(opts *ReadOptions, dx10 *bcn.DDSHeaderDX10) bool {
+	return opts != nil && opts.Linearize && detectColorSpace(dx10) == ColorSpaceSRGB
+}
+
+// srgbToLinearF maps 8-bit sRGB values to linear 0..1 intensities.
+var srgbToLinearF = func() [256]float32 {
+	var lut [256]float32
+	for i := range lut {
+		v := float64(i) / 255
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		lut[i] = float32(v)
+	}
+	return lut
+}()
+
+// linearizeImage returns a decoded NRGBA image with RGB converted from sRGB
+// to linear. The result is 16 bits per channel so dark sRGB codes keep distinct
+// linear values; alpha is already linear and widened unchanged.
+// Other image types are returned as is.
+func linearizeImage(img image.Image) image.Image {
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok {
+		return img
+	}
+
+	width, height := nrgba.Rect.Dx(), nrgba.Rect.Dy()
+	out := image.NewNRGBA64(image.Rect(0, 0, width, height))
+	for y := range height {
+		src := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+width*4]
+		dst := out.Pix[y*out.Stride:]
+		for i, v := range src {
+			value := uint16(v) * 0x101
+			if i%4 != 3 {
+				value = uint16(srgbToLinearF[v]*0xffff + 0.5)
+			}
+			dst[2*i] = uint8(value >> 8)
+			dst[2*i+1] = uint8(value)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,169 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestColorSpaceHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		format     bcn.Format
+		colorSpace ColorSpace
+		dxgi       uint32
+	}{
+		{name: "legacy", format: bcn.FormatDXT5, colorSpace: ColorSpaceUnspecified},
+		{name: "linear", format: bcn.FormatDXT5, colorSpace: ColorSpaceLinear, dxgi: 77},
+		{name: "srgb-dxt5", format: bcn.FormatDXT5, colorSpace: ColorSpaceSRGB, dxgi: 78},
+		{name: "srgb-bc7", format: bcn.FormatBC7, colorSpace: ColorSpaceSRGB, dxgi: 99},
+		{name: "srgb-bgra8", format: bcn.FormatBGRA8, colorSpace: ColorSpaceSRGB, dxgi: 91},
+	}
+
+	img := solidImage(8, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := EncodeWithOptions(&buf, img, &WriteOptions{Format: tc.format, ColorSpace: tc.colorSpace}); err != nil {
+				t.Fatalf("EncodeWithOptions: %v", err)
+			}
+
+			info, err := Inspect(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if info.ColorSpace != tc.colorSpace || info.DXGIFormat != tc.dxgi || info.Format != tc.format {
+				t.Fatalf("info color space %v, DXGI %d, format %v; want %v, %d, %v",
+					info.ColorSpace, info.DXGIFormat, info.Format, tc.colorSpace, tc.dxgi, tc.format)
+			}
+
+			cfg, err := DecodeTextureConfig(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("DecodeTextureConfig: %v", err)
+			}
+			if cfg.ColorSpace != tc.colorSpace || cfg.Format != tc.format || cfg.Width != 8 || cfg.MipMapCount != 4 {
+				t.Fatalf("config = %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestColorSpaceRejectsFormatsWithoutSRGB(t *testing.T) {
+	t.Parallel()
+
+	err := EncodeWithOptions(&bytes.Buffer{}, solidImage(4, color.NRGBA{A: 255}), &WriteOptions{
+		Format:     bcn.FormatBC5,
+		ColorSpace: ColorSpaceSRGB,
+	})
+	if !errors.Is(err, ErrInvalidColorSpace) {
+		t.Fatalf("error = %v, want ErrInvalidColorSpace", err)
+	}
+}
+
+func TestLinearizeOnDecode(t *testing.T) {
+	t.Parallel()
+
+	// Blue is a dark sRGB code that 8-bit linear output would round to 0.
+	src := solidImage(4, color.NRGBA{R: 128, G: 255, B: 3, A: 128})
+	encode := func(colorSpace ColorSpace) []byte {
+		var buf bytes.Buffer
+		if err := EncodeWithOptions(&buf, src, &WriteOptions{
+			Format:     bcn.FormatRGBA8,
+			ColorSpace: colorSpace,
+			MaxMipMaps: 1,
+		}); err != nil {
+			t.Fatalf("EncodeWithOptions: %v", err)
+		}
+		return buf.Bytes()
+	}
+	srgb := encode(ColorSpaceSRGB)
+	linear := encode(ColorSpaceLinear)
+	opts := &ReadOptions{Linearize: true}
+
+	pixel := func(img image.Image) color.NRGBA64 {
+		return img.(*image.NRGBA64).NRGBA64At(0, 0)
+	}
+
+	img, err := DecodeWithOptions(bytes.NewReader(srgb), opts)
+	if err != nil {
+		t.Fatalf("DecodeWithOptions: %v", err)
+	}
+	if got, want := pixel(img), (color.NRGBA64{R: 14146, G: 0xffff, B: 60, A: 0x8080}); got != want {
+		t.Fatalf("linearized pixel = %v, want %v", got, want)
+	}
+
+	mips, err := DecodeAll(bytes.NewReader(srgb), opts)
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	if got := pixel(mips[0]); got.R != 14146 {
+		t.Fatalf("DecodeAll red = %d, want 14146", got.R)
+	}
+
+	f, err := OpenWithOptions(bytes.NewReader(srgb), int64(len(srgb)), opts)
+	if err != nil {
+		t.Fatalf("OpenWithOptions: %v", err)
+	}
+	fileImg, err := f.Image(0)
+	if err != nil {
+		t.Fatalf("File.Image: %v", err)
+	}
+	if got := pixel(fileImg); got.R != 14146 {
+		t.Fatalf("File.Image red = %d, want 14146", got.R)
+	}
+
+	img, err = DecodeWithOptions(bytes.NewReader(linear), opts)
+	if err != nil {
+		t.Fatalf("DecodeWithOptions: %v", err)
+	}
+	if got := img.(*image.NRGBA).NRGBAAt(0, 0); got != src.NRGBAAt(0, 0) {
+		t.Fatalf("linear texture pixel = %v, want unchanged %v", got, src.NRGBAAt(0, 0))
+	}
+}
+
+func TestLinearizeCountsImageLimit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, solidImage(16, color.NRGBA{R: 128, A: 255}), &WriteOptions{
+		Format:     bcn.FormatRGBA8,
+		ColorSpace: ColorSpaceSRGB,
+		MaxMipMaps: 1,
+	}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	data := buf.Bytes()
+
+	// 16x16 NRGBA is 1024 bytes; linearizing also holds a 2048-byte NRGBA64.
+	plain := &ReadOptions{MaxImageBytes: 2048}
+	linear := &ReadOptions{MaxImageBytes: 2048, Linearize: true}
+	if _, err := DecodeWithOptions(bytes.NewReader(data), plain); err != nil {
+		t.Fatalf("DecodeWithOptions without Linearize: %v", err)
+	}
+	if _, err := DecodeWithOptions(bytes.NewReader(data), linear); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("DecodeWithOptions error = %v, want ErrReadLimitExceeded", err)
+	}
+	if _, err := DecodeAll(bytes.NewReader(data), linear); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("DecodeAll error = %v, want ErrReadLimitExceeded", err)
+	}
+
+	f, err := OpenWithOptions(bytes.NewReader(data), int64(len(data)), linear)
+	if err != nil {
+		t.Fatalf("OpenWithOptions: %v", err)
+	}
+	if _, err := f.Image(0); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("File.Image error = %v, want ErrReadLimitExceeded", err)
+	}
+	// Payloads are not decoded, so the image limit does not apply.
+	if _, err := f.Payload(0); err != nil {
+		t.Fatalf("File.Payload: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ package edds
 import (
 	"encoding/binary"
 	"fmt"
+	"strings"
 
 	"github.com/pierrec/lz4/v4"
 )
@@ -66,6 +67,18 @@ func (m CompressionMode) String() string {
 	}
 }
 
+// ParseCompressionMode returns the concrete compression mode with the given name
+// ("None", "LZ4" or "LZ4HC"), ignoring case.
+func ParseCompressionMode(name string) (CompressionMode, error) {
+	for _, mode := range []CompressionMode{CompressionNone, CompressionLZ4, CompressionLZ4HC} {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+
+	return CompressionDefault, fmt.Errorf("%w: mode %q", ErrInvalidCompressionOptions, name)
+}
+
 // isValid reports whether the compression mode is a concrete supported mode.
 func (m CompressionMode) isValid() bool {
 	switch m {
@@ -479,23 +492,7 @@ func (d *blockDecompressor) decompressBlock(dst []byte, block *Block, expectedUn
 
 		outIdx += n
 
-		decoded := target[outIdx-n : outIdx]
-		// LZ4 block mode uses the previous 64 KiB of decoded bytes as dictionary.
-		if len(decoded) >= dictCap {
-			copy(dict, decoded[len(decoded)-dictCap:])
-			dictSize = dictCap
-		} else {
-			avail := dictCap - dictSize
-			if len(decoded) <= avail {
-				copy(dict[dictSize:], decoded)
-				dictSize += len(decoded)
-			} else {
-				shift := len(decoded) - avail
-				copy(dict, dict[shift:dictSize])
-				copy(dict[dictCap-len(decoded):], decoded)
-				dictSize = dictCap
-			}
-		}
+		dictSize = pushDict(dict, dictSize, target[outIdx-n:outIdx])
 
 		if (flags & 0x80) != 0 {
 			break
@@ -512,6 +509,28 @@ func (d *blockDecompressor) decompressBlock(dst []byte, block *Block, expectedUn
 	return target, nil
 }
 
+// pushDict appends decoded to the rolling dictionary holding dictSize bytes
+// and returns its new size. LZ4 block mode uses the previous 64 KiB
+// of decoded bytes, the length of dict, as dictionary.
+func pushDict(dict []byte, dictSize int, decoded []byte) int {
+	dictCap := len(dict)
+	if len(decoded) >= dictCap {
+		copy(dict, decoded[len(decoded)-dictCap:])
+		return dictCap
+	}
+
+	avail := dictCap - dictSize
+	if len(decoded) <= avail {
+		copy(dict[dictSize:], decoded)
+		return dictSize + len(decoded)
+	}
+
+	shift := len(decoded) - avail
+	copy(dict, dict[shift:dictSize])
+	copy(dict[dictCap-len(decoded):], decoded)
+	return dictCap
+}
+
 // ensureLen returns b resized to n, allocating only when capacity is insufficient.
 func ensureLen(b []byte, n int) []byte {
 	if cap(b) < n {
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// convertedDDS holds a plain DDS texture regrouped into EDDS mip blocks, largest first.
+type convertedDDS struct {
+	levels     [][]byte
+	layout     textureLayout
+	format     bcn.Format
+	colorSpace ColorSpace
+	width      int
+	height     int
+}
+
+// ConvertOptions configures ConvertDDS.
+type ConvertOptions struct {
+	// Compression selects the block compression of the output.
+	Compression CompressionOptions
+	// Read limits the DDS input. Nil uses default limits. Pixels are not
+	// decoded, so MaxImageBytes and Linearize do not apply.
+	Read *ReadOptions
+}
+
+// ConvertDDS converts a plain DDS stream into an EDDS stream without re-encoding pixels.
+// Mip payloads are copied as stored and compressed into blocks with opts.Compression;
+// the output gets the ENF1 header. Cubemaps, texture arrays, and volume textures
+// are regrouped from the DDS per-surface order into one block per mip level.
+// Bytes after the last mip are rejected with ErrTrailingData. Nil opts uses
+// default LZ4 compression and read limits.
+func ConvertDDS(r io.Reader, w io.Writer, opts *ConvertOptions) error {
+	limits, err := convertReadLimits(opts)
+	if err != nil {
+		return err
+	}
+	dds, err := readPlainDDS(r, limits)
+	if err != nil {
+		return err
+	}
+
+	return dds.write(w, opts)
+}
+
+// ConvertDDSFile converts the plain DDS file at src into the EDDS file dst.
+// dst is replaced atomically and may be the same path as src.
+func ConvertDDSFile(src, dst string, opts *ConvertOptions) error {
+	limits, err := convertReadLimits(opts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrOpenFile, src, err)
+	}
+	if err := validateInputFileSize(f, limits); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	// Close src before replacing dst so in-place conversion works on every platform.
+	dds, err := readPlainDDS(f, limits)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(dst, func(f *os.File) error {
+		return dds.write(NewStreamWriter(f), opts)
+	})
+}
+
+// convertReadLimits returns the payload-only read limits of opts.
+func convertReadLimits(opts *ConvertOptions) (readLimits, error) {
+	var read *ReadOptions
+	if opts != nil {
+		read = opts.Read
+	}
+	limits, err := normalizeReadLimits(read)
+	if err != nil {
+		return readLimits{}, err
+	}
+
+	return limits.forPayloads(), nil
+}
+
+// readPlainDDS reads DDS headers and the contiguous surface data that follows them.
+// DDS stores every array slice or cubemap face as a full mip chain, largest first;
+// a volume stores all depth slices of a level before the next level.
+// Surfaces are read straight into their level blocks, so the payload is held once.
+func readPlainDDS(r io.Reader, limits readLimits) (*convertedDDS, error) {
+	header, dx10, err := readEDDSHeaders(r)
+	if err != nil {
+		return nil, err
+	}
+	if header.Reserved1[1] == enfusionMarker {
+		return nil, fmt.Errorf("%w: input has the ENF1 marker; use Recompress", ErrNotPlainDDS)
+	}
+
+	layout, err := readTextureLayout(header, dx10)
+	if err != nil {
+		return nil, err
+	}
+	format := detectFormat(header, dx10)
+	if format == bcn.FormatUnknown {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, intToFourCC(header.PixelFormat.FourCC))
+	}
+	mipMapCount, err := readMipMapCount(header, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := int(header.Width), int(header.Height)
+	if err := validateMipChainLimits(format, width, height, mipMapCount, layout, limits); err != nil {
+		return nil, err
+	}
+
+	// Per-surface chains: faces*arraySize chains, each holding every level;
+	// a level of a volume chain holds all of its depth slices.
+	chains := layout.faces * layout.arraySize
+	levelSizes := make([]int, mipMapCount)
+	for level := range levelSizes {
+		size, err := expectedDataLengthChecked(format, mipDimension(width, level), mipDimension(height, level))
+		if err != nil {
+			return nil, err
+		}
+		levelSizes[level] = size * mipDimension(layout.depth, level)
+	}
+
+	dds := &convertedDDS{
+		levels:     make([][]byte, mipMapCount),
+		layout:     layout,
+		format:     format,
+		colorSpace: detectColorSpace(dx10),
+		width:      width,
+		height:     height,
+	}
+	for level, size := range levelSizes {
+		dds.levels[level] = make([]byte, size*chains)
+	}
+	for chain := range chains {
+		for level, size := range levelSizes {
+			if _, err := io.ReadFull(r, dds.levels[level][chain*size:(chain+1)*size]); err != nil {
+				return nil, fmt.Errorf("%w: surface %d mipmap %d: %v", ErrReadRemainingData, chain, level, err)
+			}
+		}
+	}
+
+	// Plain DDS ends with the last mip, so extra bytes mean a misread header.
+	var probe [1]byte
+	if _, err := io.ReadFull(r, probe[:]); err == nil {
+		return nil, fmt.Errorf("%w: after %d mipmaps", ErrTrailingData, mipMapCount)
+	} else if err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrReadRemainingData, err)
+	}
+
+	return dds, nil
+}
+
+// write writes the converted texture as EDDS blocks compressed with opts.Compression.
+func (c *convertedDDS) write(w io.Writer, opts *ConvertOptions) error {
+	var options CompressionOptions
+	if opts != nil {
+		options = opts.Compression
+	}
+	compression, err := normalizeCompressionOptions(options, true)
+	if err != nil {
+		return err
+	}
+
+	return NewEncoder().writeFromBlocks(w, c.format, c.colorSpace, c.width, c.height, c.layout, c.levels, compression, 1)
+}
@@ -0,0 +1,146 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+// plainDDS builds a standard DDS stream: headers without the ENF1 marker,
+// then every surface as a full mip chain, largest first.
+func plainDDS(t *testing.T, format bcn.Format, layout textureLayout, surfaces []image.Image, mipMapCount int) []byte {
+	t.Helper()
+
+	size := surfaces[0].Bounds().Dx()
+	header, dx10, err := makeDDSHeaders(uint32(size), uint32(size), uint32(mipMapCount), format, ColorSpaceUnspecified, layout)
+	if err != nil {
+		t.Fatalf("makeDDSHeaders: %v", err)
+	}
+	header.Reserved1 = [11]uint32{}
+
+	var buf bytes.Buffer
+	if err := writeDDSHeaders(&buf, header, dx10); err != nil {
+		t.Fatalf("writeDDSHeaders: %v", err)
+	}
+	for _, surface := range surfaces {
+		for _, mip := range bcn.GenerateMipmapsN(surface, mipMapCount, false) {
+			data, _, _, err := bcn.EncodeImageWithOptions(mip, format, nil)
+			if err != nil {
+				t.Fatalf("EncodeImageWithOptions: %v", err)
+			}
+			buf.Write(data)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func TestConvertDDSKeepsPayloads(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 16), G: uint8(y * 16), B: 80, A: 255}) //nolint:gosec // bounded
+		}
+	}
+	src := plainDDS(t, bcn.FormatDXT5, texture2D, []image.Image{img}, 5)
+
+	var out bytes.Buffer
+	if err := ConvertDDS(bytes.NewReader(src), &out, &ConvertOptions{Compression: CompressionOptions{Mode: CompressionLZ4}}); err != nil {
+		t.Fatalf("ConvertDDS: %v", err)
+	}
+
+	info, err := Inspect(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !info.Enfusion || info.Format != bcn.FormatDXT5 || len(info.Blocks) != 5 {
+		t.Fatalf("info enfusion %v, format %v, blocks %d", info.Enfusion, info.Format, len(info.Blocks))
+	}
+
+	payloads, err := DecodePayloads(bytes.NewReader(out.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodePayloads: %v", err)
+	}
+	offset := len(src) - (16*16 + 8*8 + 4*4 + 16 + 16)
+	for level, payload := range payloads.Mipmaps {
+		if !bytes.Equal(payload, src[offset:offset+len(payload)]) {
+			t.Fatalf("mipmap %d payload differs from DDS data", level)
+		}
+		offset += len(payload)
+	}
+}
+
+func TestConvertDDSCubemapFaceOrder(t *testing.T) {
+	t.Parallel()
+
+	faces := make([]image.Image, cubeFaceCount)
+	for face, c := range cubeFaceColors {
+		faces[face] = solidImage(8, c)
+	}
+	src := plainDDS(t, bcn.FormatBGRA8, cubeLayout, faces, 4)
+
+	path := filepath.Join(t.TempDir(), "sky.edds")
+	srcPath := filepath.Join(t.TempDir(), "sky.dds")
+	if err := os.WriteFile(srcPath, src, 0o600); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	if err := ConvertDDSFile(srcPath, path, &ConvertOptions{Compression: CompressionOptions{Mode: CompressionNone}}); err != nil {
+		t.Fatalf("ConvertDDSFile: %v", err)
+	}
+
+	cube, err := ReadCube(path, nil)
+	if err != nil {
+		t.Fatalf("ReadCube: %v", err)
+	}
+	for face, c := range cubeFaceColors {
+		for level, mip := range cube.Faces[face] {
+			if got := mip.(*image.NRGBA).NRGBAAt(0, 0); got != c {
+				t.Fatalf("face %d level %d = %v, want %v", face, level, got, c)
+			}
+		}
+	}
+}
+
+func TestConvertDDSErrors(t *testing.T) {
+	t.Parallel()
+
+	var edds bytes.Buffer
+	if err := Encode(&edds, solidImage(4, color.NRGBA{A: 255})); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := ConvertDDS(bytes.NewReader(edds.Bytes()), &bytes.Buffer{}, nil); !errors.Is(err, ErrNotPlainDDS) {
+		t.Fatalf("EDDS input error = %v, want ErrNotPlainDDS", err)
+	}
+
+	src := plainDDS(t, bcn.FormatBGRA8, texture2D, []image.Image{solidImage(4, color.NRGBA{A: 255})}, 3)
+	if err := ConvertDDS(bytes.NewReader(src[:len(src)-1]), &bytes.Buffer{}, nil); !errors.Is(err, ErrReadRemainingData) {
+		t.Fatalf("truncated input error = %v, want ErrReadRemainingData", err)
+	}
+	if err := ConvertDDS(bytes.NewReader(append(src, 0)), &bytes.Buffer{}, nil); !errors.Is(err, ErrTrailingData) {
+		t.Fatalf("trailing input error = %v, want ErrTrailingData", err)
+	}
+}
+
+func TestConvertDDSReadLimits(t *testing.T) {
+	t.Parallel()
+
+	src := plainDDS(t, bcn.FormatDXT5, texture2D, []image.Image{solidImage(16, color.NRGBA{R: 200, A: 255})}, 5)
+
+	// Payloads are copied without decoding, so only payload limits apply.
+	opts := &ConvertOptions{Read: &ReadOptions{MaxImageBytes: 16}}
+	if err := ConvertDDS(bytes.NewReader(src), &bytes.Buffer{}, opts); err != nil {
+		t.Fatalf("ConvertDDS with MaxImageBytes: %v", err)
+	}
+	opts.Read = &ReadOptions{MaxDecodedBytes: 256}
+	if err := ConvertDDS(bytes.NewReader(src), &bytes.Buffer{}, opts); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("ConvertDDS error = %v, want ErrReadLimitExceeded", err)
+	}
+}
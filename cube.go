@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// CubeFace indexes a cubemap face in DDS order.
+type CubeFace int
+
+const (
+	// CubeFacePositiveX is the +X face.
+	CubeFacePositiveX CubeFace = iota
+	// CubeFaceNegativeX is the -X face.
+	CubeFaceNegativeX
+	// CubeFacePositiveY is the +Y face.
+	CubeFacePositiveY
+	// CubeFaceNegativeY is the -Y face.
+	CubeFaceNegativeY
+	// CubeFacePositiveZ is the +Z face.
+	CubeFacePositiveZ
+	// CubeFaceNegativeZ is the -Z face.
+	CubeFaceNegativeZ
+)
+
+// CubeTexture holds the six faces of a cubemap.
+// EDDS stores one block per mip level holding all six faces in CubeFace order.
+// This layout follows DDS conventions and has not been checked against a
+// Workbench-produced cubemap; the test corpus holds none.
+type CubeTexture struct {
+	// Faces holds a mip chain per face, indexed by CubeFace
+	// and ordered from largest (level 0) to smallest.
+	// Writers generate mipmaps from WriteOptions when every face holds only level 0;
+	// otherwise every face must hold a chain of the same length, which is written
+	// as given like EncodeMipImages does.
+	Faces [cubeFaceCount][]image.Image
+	// Format is the stored pixel format; it is set by readers and ignored by writers.
+	Format bcn.Format
+}
+
+// ReadCube reads and decodes every face and mip level of an EDDS cubemap file.
+func ReadCube(path string, opts *ReadOptions) (*CubeTexture, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := validateInputFileSize(f, limits); err != nil {
+		return nil, err
+	}
+
+	return NewDecoder().DecodeCube(f, opts)
+}
+
+// DecodeCube reads and decodes every face and mip level of an EDDS cubemap stream.
+func DecodeCube(r io.Reader, opts *ReadOptions) (*CubeTexture, error) {
+	return NewDecoder().DecodeCube(r, opts)
+}
+
+// DecodeCube reads and decodes every face and mip level of an EDDS cubemap stream.
+// Returned images own their pixel buffers and stay valid after later calls on the same Decoder.
+func (d *Decoder) DecodeCube(r io.Reader, opts *ReadOptions) (*CubeTexture, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	decOpts := (*bcn.DecodeOptions)(nil)
+	if opts != nil {
+		decOpts = opts.DecodeOptions
+	}
+
+	cube := &CubeTexture{}
+	var linearize bool
+	err = d.readMipChain(r, limits, func(chain *mipChain) error {
+		if chain.layout != cubeLayout {
+			return fmt.Errorf("%w: %s is not a cubemap", ErrUnsupportedTextureType, chain.layout.describe())
+		}
+		cube.Format = chain.format
+		linearize = linearizes(opts, chain.dx10)
+		for face := range cube.Faces {
+			cube.Faces[face] = make([]image.Image, chain.mipMapCount)
+		}
+		return nil
+	}, func(level, width, height int, payload []byte) error {
+		faceSize := len(payload) / cubeFaceCount
+		for face := range cube.Faces {
+			img, err := decodeImage(payload[face*faceSize:(face+1)*faceSize], width, height, cube.Format, decOpts)
+			if err != nil {
+				return fmt.Errorf("%w: face %d mipmap %d: %v", ErrDecodeImage, face, level, err)
+			}
+			if linearize {
+				img = linearizeImage(img)
+			}
+			cube.Faces[face][level] = img
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cube, nil
+}
+
+// WriteCube writes an EDDS cubemap file.
+func WriteCube(cube *CubeTexture, path string, opts *WriteOptions) error {
+	enc := NewEncoder()
+	return writeFileAtomic(path, func(f *os.File) error {
+		return enc.EncodeCube(NewStreamWriter(f), cube, opts)
+	})
+}
+
+// EncodeCube writes an EDDS cubemap stream.
+func EncodeCube(w io.Writer, cube *CubeTexture, opts *WriteOptions) error {
+	return NewEncoder().EncodeCube(w, cube, opts)
+}
+
+// EncodeCube writes an EDDS cubemap stream.
+// Level 0 of every face must be square and the same size.
+// Faces holding only level 0 get mipmaps generated according to opts;
+// faces holding full chains are written as given, see CubeTexture.Faces.
+func (e *Encoder) EncodeCube(w io.Writer, cube *CubeTexture, opts *WriteOptions) error {
+	size, err := cube.faceSize()
+	if err != nil {
+		return err
+	}
+
+	cfg := normalizeWriteOptions(opts)
+	if err := validateWriteOptions(&cfg); err != nil {
+		return err
+	}
+
+	levels, err := e.encodeChains(cube.Faces[:], size, size, &cfg, ErrInvalidCubeTexture, "face")
+	if err != nil {
+		return err
+	}
+
+	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
+	if err != nil {
+		return err
+	}
+
+	return e.writeFromBlocks(w, cfg.Format, cfg.ColorSpace, size, size, cubeLayout, levels, compression, cfg.Concurrency)
+}
+
+// faceSize validates level 0 of every face and returns the shared edge length.
+func (c *CubeTexture) faceSize() (int, error) {
+	if c == nil {
+		return 0, fmt.Errorf("%w: nil cubemap", ErrInvalidCubeTexture)
+	}
+
+	width, height, err := sharedImageSize(baseLevels(c.Faces[:]), ErrInvalidCubeTexture, "face")
+	if err != nil {
+		return 0, err
+	}
+	if width != height {
+		return 0, fmt.Errorf("%w: faces are %dx%d, want square", ErrInvalidCubeTexture, width, height)
+	}
+
+	return width, nil
+}
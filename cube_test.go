@@ -0,0 +1,181 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+// cubeFaceColors are distinct per-face colors used to verify face order.
+var cubeFaceColors = [cubeFaceCount]color.NRGBA{
+	{R: 255, A: 255},
+	{G: 255, A: 255},
+	{B: 255, A: 255},
+	{R: 255, G: 255, A: 255},
+	{G: 255, B: 255, A: 255},
+	{R: 255, B: 255, A: 255},
+}
+
+func solidImage(size int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+
+	return img
+}
+
+func TestCubeRoundTripFaceOrder(t *testing.T) {
+	t.Parallel()
+
+	var cube CubeTexture
+	for face, c := range cubeFaceColors {
+		cube.Faces[face] = []image.Image{solidImage(16, c)}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeCube(&buf, &cube, &WriteOptions{
+		Format:      bcn.FormatBGRA8,
+		Compression: CompressionOptions{Mode: CompressionNone},
+	}); err != nil {
+		t.Fatalf("EncodeCube: %v", err)
+	}
+	data := buf.Bytes()
+
+	// The writer stores one block per level with faces in DDS order +X, -X, +Y, -Y, +Z, -Z.
+	info, err := Inspect(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.Header.Caps2 != bcn.DDSCaps2Cubemap|ddsCaps2CubemapAllFaces || len(info.Blocks) != 5 {
+		t.Fatalf("caps2 = 0x%x, blocks %d", info.Header.Caps2, len(info.Blocks))
+	}
+	level0 := info.Blocks[len(info.Blocks)-1]
+	faceSize := 16 * 16 * 4
+	if int(level0.Size) != cubeFaceCount*faceSize {
+		t.Fatalf("level 0 block size = %d, want %d", level0.Size, cubeFaceCount*faceSize)
+	}
+	for face, c := range cubeFaceColors {
+		got := data[level0.Offset+int64(face*faceSize):][:4]
+		if !bytes.Equal(got, []byte{c.B, c.G, c.R, c.A}) {
+			t.Fatalf("face %d stored pixel = %v, want %v", face, got, c)
+		}
+	}
+
+	// Parallel block decompression must split every level into the same faces.
+	for _, opts := range []*ReadOptions{nil, {Concurrency: 3}} {
+		decoded, err := DecodeCube(bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf("DecodeCube: %v", err)
+		}
+		for face, c := range cubeFaceColors {
+			mips := decoded.Faces[face]
+			if len(mips) != 5 {
+				t.Fatalf("face %d has %d levels, want 5", face, len(mips))
+			}
+			for level, mip := range mips {
+				if mip.Bounds().Dx() != 16>>level {
+					t.Fatalf("face %d level %d bounds = %v", face, level, mip.Bounds())
+				}
+				if got := color.NRGBAModel.Convert(mip.At(0, 0)); got != c {
+					t.Fatalf("face %d level %d color = %v, want %v", face, level, got, c)
+				}
+			}
+		}
+	}
+
+	if _, err := Decode(bytes.NewReader(data)); !errors.Is(err, ErrUnsupportedTextureType) {
+		t.Fatalf("Decode cubemap error = %v, want ErrUnsupportedTextureType", err)
+	}
+}
+
+func TestWriteCubeDXT5(t *testing.T) {
+	t.Parallel()
+
+	var cube CubeTexture
+	for face, c := range cubeFaceColors {
+		cube.Faces[face] = []image.Image{solidImage(32, c)}
+	}
+
+	path := filepath.Join(t.TempDir(), "sky.edds")
+	if err := WriteCube(&cube, path, &WriteOptions{Format: bcn.FormatDXT5}); err != nil {
+		t.Fatalf("WriteCube: %v", err)
+	}
+	got, err := ReadCube(path, nil)
+	if err != nil {
+		t.Fatalf("ReadCube: %v", err)
+	}
+	if got.Format != bcn.FormatDXT5 || len(got.Faces[CubeFaceNegativeZ]) != 6 {
+		t.Fatalf("ReadCube = %v with %d levels", got.Format, len(got.Faces[CubeFaceNegativeZ]))
+	}
+}
+
+func TestEncodeCubeRejectsMismatchedFaces(t *testing.T) {
+	t.Parallel()
+
+	var cube CubeTexture
+	for face := range cube.Faces {
+		cube.Faces[face] = []image.Image{solidImage(8, cubeFaceColors[face])}
+	}
+	cube.Faces[CubeFacePositiveY] = []image.Image{solidImage(4, cubeFaceColors[CubeFacePositiveY])}
+
+	if err := EncodeCube(&bytes.Buffer{}, &cube, nil); !errors.Is(err, ErrInvalidCubeTexture) {
+		t.Fatalf("EncodeCube error = %v, want ErrInvalidCubeTexture", err)
+	}
+}
+
+func TestEncodeCubeSuppliedChains(t *testing.T) {
+	t.Parallel()
+
+	// Level 1 is black on every face, which generated mipmaps would never produce.
+	var cube CubeTexture
+	for face, c := range cubeFaceColors {
+		cube.Faces[face] = []image.Image{solidImage(8, c), solidImage(4, color.NRGBA{A: 255}), solidImage(2, c)}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeCube(&buf, &cube, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeCube: %v", err)
+	}
+	got, err := DecodeCube(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeCube: %v", err)
+	}
+	for face, c := range cubeFaceColors {
+		mips := got.Faces[face]
+		if len(mips) != 3 {
+			t.Fatalf("face %d has %d levels, want 3", face, len(mips))
+		}
+		if level1 := color.NRGBAModel.Convert(mips[1].At(0, 0)); level1 != (color.NRGBA{A: 255}) {
+			t.Fatalf("face %d level 1 = %v, want supplied black", face, level1)
+		}
+		if level2 := color.NRGBAModel.Convert(mips[2].At(0, 0)); level2 != c {
+			t.Fatalf("face %d level 2 = %v, want %v", face, level2, c)
+		}
+	}
+
+	// A decoded cubemap round-trips its chains; MaxMipMaps cuts them.
+	buf.Reset()
+	if err := EncodeCube(&buf, got, &WriteOptions{Format: bcn.FormatBGRA8, MaxMipMaps: 2}); err != nil {
+		t.Fatalf("EncodeCube decoded: %v", err)
+	}
+	info, err := Inspect(bytes.NewReader(buf.Bytes()))
+	if err != nil || len(info.Blocks) != 2 {
+		t.Fatalf("Inspect = %d blocks, err %v", len(info.Blocks), err)
+	}
+
+	// Every face must hold the same number of valid levels.
+	cube.Faces[CubeFaceNegativeZ] = cube.Faces[CubeFaceNegativeZ][:2]
+	if err := EncodeCube(&bytes.Buffer{}, &cube, nil); !errors.Is(err, ErrInvalidCubeTexture) {
+		t.Fatalf("uneven chains error = %v, want ErrInvalidCubeTexture", err)
+	}
+	cube.Faces[CubeFaceNegativeZ] = []image.Image{solidImage(8, cubeFaceColors[CubeFaceNegativeZ]), solidImage(8, color.NRGBA{}), solidImage(2, color.NRGBA{})}
+	if err := EncodeCube(&bytes.Buffer{}, &cube, nil); !errors.Is(err, ErrMipmapSizeMismatch) {
+		t.Fatalf("bad chain error = %v, want ErrMipmapSizeMismatch", err)
+	}
+}
@@ -15,6 +15,8 @@ level into RGBA, and write uncompressed RGBA payloads with optional mipmaps.
 
 Package-level Read and Write helpers operate on file paths.
 Decode and Encode operate on io.Reader and io.Writer streams.
+Importing the package registers the "edds" format with the standard
+image package, so image.Decode and image.DecodeConfig accept EDDS input.
 EncodeFromBlocks writes pre-encoded mipmap payloads
 to an io.Writer without re-encoding image pixels.
 
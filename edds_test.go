@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"image"
 	"image/color"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/woozymasta/bcn"
@@ -160,6 +163,137 @@ func TestStreamEncodeDecode(t *testing.T) {
 	}
 }
 
+// seekBuffer is an in-memory io.WriteSeeker that counts seeks and can refuse them like a pipe.
+type seekBuffer struct {
+	data     []byte
+	pos      int64
+	seeks    int
+	failSeek bool
+}
+
+func (b *seekBuffer) Write(p []byte) (int, error) {
+	if end := int(b.pos) + len(p); end > len(b.data) {
+		b.data = append(b.data, make([]byte, end-len(b.data))...)
+	}
+	n := copy(b.data[b.pos:], p)
+	b.pos += int64(n)
+	return n, nil
+}
+
+func (b *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	if b.failSeek {
+		return 0, errors.New("illegal seek")
+	}
+	b.seeks++
+	switch whence {
+	case io.SeekCurrent:
+		offset += b.pos
+	case io.SeekEnd:
+		offset += int64(len(b.data))
+	}
+	b.pos = offset
+	return offset, nil
+}
+
+func TestEncodeStreamWriterStreamsBlocks(t *testing.T) {
+	t.Parallel()
+
+	img := gradientNRGBA(64, 64)
+	for _, mode := range []CompressionMode{CompressionNone, CompressionLZ4, CompressionLZ4HC} {
+		opts := &WriteOptions{Format: bcn.FormatBGRA8, Compression: CompressionOptions{Mode: mode}}
+		var want bytes.Buffer
+		if err := EncodeWithOptions(&want, img, opts); err != nil {
+			t.Fatalf("%s buffered: %v", mode, err)
+		}
+
+		// The stream starts after existing data, so the table offset is relative to it.
+		prefix := []byte("pack")
+		out := &seekBuffer{}
+		_, _ = out.Write(prefix)
+		if err := EncodeWithOptions(NewStreamWriter(out), img, opts); err != nil {
+			t.Fatalf("%s streamed: %v", mode, err)
+		}
+		if out.seeks == 0 {
+			t.Fatalf("%s: StreamWriter output was not streamed", mode)
+		}
+		if !bytes.Equal(out.data[len(prefix):], want.Bytes()) || out.pos != int64(len(out.data)) {
+			t.Fatalf("%s: streamed output differs from buffered", mode)
+		}
+
+		// Plain writers are never seeked, even when they implement io.Seeker.
+		plain := &seekBuffer{}
+		if err := EncodeWithOptions(plain, img, opts); err != nil {
+			t.Fatalf("%s plain: %v", mode, err)
+		}
+		if plain.seeks != 0 || !bytes.Equal(plain.data, want.Bytes()) {
+			t.Fatalf("%s: plain io.WriteSeeker output was streamed or differs", mode)
+		}
+
+		// Parallel writes buffer the chain and write it sequentially.
+		parallel := &seekBuffer{}
+		concurrent := *opts
+		concurrent.Concurrency = 4
+		if err := EncodeWithOptions(NewStreamWriter(parallel), img, &concurrent); err != nil {
+			t.Fatalf("%s parallel: %v", mode, err)
+		}
+		if parallel.seeks != 0 || !bytes.Equal(parallel.data, want.Bytes()) {
+			t.Fatalf("%s: parallel StreamWriter output was streamed or differs", mode)
+		}
+
+		// A StreamWriter that cannot seek fails before writing anything.
+		pipe := &seekBuffer{failSeek: true}
+		if err := EncodeWithOptions(NewStreamWriter(pipe), img, opts); !errors.Is(err, ErrSeekOutput) {
+			t.Fatalf("%s non-seekable error = %v, want ErrSeekOutput", mode, err)
+		}
+		if len(pipe.data) != 0 {
+			t.Fatalf("%s: non-seekable StreamWriter wrote %d bytes", mode, len(pipe.data))
+		}
+	}
+
+	// Files written through the atomic writer stream as well.
+	path := filepath.Join(t.TempDir(), "streamed.edds")
+	if err := WriteWithOptions(img, path, nil); err != nil {
+		t.Fatalf("WriteWithOptions: %v", err)
+	}
+	var want bytes.Buffer
+	if err := EncodeWithOptions(&want, img, nil); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Fatal("WriteWithOptions output differs from buffered")
+	}
+
+	// Appending to a file keeps working through the buffered path.
+	appendPath := filepath.Join(t.TempDir(), "append.edds")
+	if err := os.WriteFile(appendPath, []byte("pack"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	appendFile, err := os.OpenFile(appendPath, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = appendFile.Close() }()
+	if err := EncodeWithOptions(appendFile, img, nil); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	got, err = os.ReadFile(appendPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got[4:], want.Bytes()) {
+		t.Fatal("appended output differs from buffered")
+	}
+
+	// Streaming into an append-only file cannot patch the table in place.
+	if err := EncodeWithOptions(NewStreamWriter(appendFile), img, nil); !errors.Is(err, ErrSeekOutput) {
+		t.Fatalf("append-only StreamWriter error = %v, want ErrSeekOutput", err)
+	}
+}
+
 func TestDecodeNonSeekableStream(t *testing.T) {
 	t.Parallel()
 
@@ -186,6 +320,60 @@ func TestDecodeNonSeekableStream(t *testing.T) {
 	}
 }
 
+func TestDecodeMip(t *testing.T) {
+	t.Parallel()
+
+	img := benchImage(64, 32)
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, img, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	want := bcn.GenerateMipmapsN(img, 0, false)
+
+	for level, wantMip := range want {
+		got, err := DecodeMip(bytes.NewReader(buf.Bytes()), level, nil)
+		if err != nil {
+			t.Fatalf("DecodeMip(%d): %v", level, err)
+		}
+		gotNRGBA, ok := got.(*image.NRGBA)
+		if !ok {
+			t.Fatalf("expected *image.NRGBA, got %T", got)
+		}
+		if gotNRGBA.Bounds() != wantMip.Bounds() || !bytes.Equal(gotNRGBA.Pix, wantMip.Pix) {
+			t.Fatalf("level %d mismatch: bounds %v, want %v", level, gotNRGBA.Bounds(), wantMip.Bounds())
+		}
+
+		streamed, err := NewDecoder().DecodeMip(&maxReadRequestReader{r: bytes.NewReader(buf.Bytes()), max: 64 * 1024}, level, nil)
+		if err != nil {
+			t.Fatalf("Decoder.DecodeMip(%d) stream: %v", level, err)
+		}
+		if !bytes.Equal(streamed.(*image.NRGBA).Pix, wantMip.Pix) {
+			t.Fatalf("level %d stream mismatch", level)
+		}
+	}
+
+	for _, level := range []int{-1, len(want)} {
+		if _, err := DecodeMip(bytes.NewReader(buf.Bytes()), level, nil); !errors.Is(err, ErrMipLevelOutOfRange) {
+			t.Fatalf("DecodeMip(%d) error = %v, want ErrMipLevelOutOfRange", level, err)
+		}
+	}
+}
+
+func TestMipErrorsAreLevelNeutral(t *testing.T) {
+	t.Parallel()
+
+	// The former largest-mip sentinels still match the errors returned for any level.
+	for _, tc := range []struct{ current, former error }{
+		{ErrMipSizeMismatch, ErrLargestMipSizeMismatch},
+		{ErrPickMip, ErrPickLargestMip},
+	} {
+		err := fmt.Errorf("%w: level 3", tc.current)
+		if !errors.Is(err, tc.former) || strings.Contains(err.Error(), "largest") {
+			t.Fatalf("error %q does not match %v or mentions the largest mip", err, tc.former)
+		}
+	}
+}
+
 func TestCurrentBlockTableErrorsDoNotFallbackToLegacy(t *testing.T) {
 	t.Parallel()
 
@@ -502,6 +690,107 @@ func TestExpectedDataLengthTable(t *testing.T) {
 	}
 }
 
+func TestWriteEveryReadableFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format bcn.Format
+		dx10   bool
+	}{
+		{format: bcn.FormatDXT1},
+		{format: bcn.FormatDXT3},
+		{format: bcn.FormatDXT5},
+		{format: bcn.FormatBC4},
+		{format: bcn.FormatBC4S},
+		{format: bcn.FormatBC5},
+		{format: bcn.FormatBC5S},
+		{format: bcn.FormatBC7, dx10: true},
+		{format: bcn.FormatRGBA8},
+		{format: bcn.FormatBGRA8},
+		{format: bcn.FormatBGRX8, dx10: true},
+		{format: bcn.FormatRGB10A2, dx10: true},
+		{format: bcn.FormatR8},
+		{format: bcn.FormatR8S, dx10: true},
+		{format: bcn.FormatRG8},
+		{format: bcn.FormatRG8S, dx10: true},
+		{format: bcn.FormatA8},
+		{format: bcn.FormatRGB565, dx10: true},
+		{format: bcn.FormatRGBA5551, dx10: true},
+		{format: bcn.FormatRGBA4444, dx10: true},
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 30), G: uint8(y * 30), B: 60, A: uint8(255 - x*10)}) //nolint:gosec // bounded
+		}
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := EncodeWithOptions(&buf, img, &WriteOptions{Format: tc.format, MaxMipMaps: 1}); err != nil {
+				t.Fatalf("EncodeWithOptions: %v", err)
+			}
+
+			info, err := Inspect(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if info.Format != tc.format {
+				t.Fatalf("format = %v, want %v", info.Format, tc.format)
+			}
+			if (info.DX10 != nil) != tc.dx10 {
+				t.Fatalf("DX10 header = %+v, want present %v", info.DX10, tc.dx10)
+			}
+
+			want, _, _, err := bcn.EncodeImageWithOptions(img, tc.format, nil)
+			if err != nil {
+				t.Fatalf("bcn.EncodeImageWithOptions: %v", err)
+			}
+			payloads, err := DecodePayloads(bytes.NewReader(buf.Bytes()), nil)
+			if err != nil {
+				t.Fatalf("DecodePayloads: %v", err)
+			}
+			if !bytes.Equal(payloads.Mipmaps[0], want) {
+				t.Fatalf("stored payload differs from direct bcn encode")
+			}
+		})
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range supportedFormats() {
+		got, err := ParseFormat(strings.ToLower(FormatName(format)))
+		if err != nil || got != format {
+			t.Fatalf("ParseFormat(%q) = %v, %v", FormatName(format), got, err)
+		}
+	}
+	if got, err := ParseFormat("DXT5"); err != nil || got != bcn.FormatDXT5 {
+		t.Fatalf("ParseFormat(DXT5) = %v, %v", got, err)
+	}
+	if _, err := ParseFormat("RGB8"); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("ParseFormat(RGB8) error = %v, want ErrInvalidFormat", err)
+	}
+
+	if got, err := ParseCompressionMode("lz4hc"); err != nil || got != CompressionLZ4HC {
+		t.Fatalf("ParseCompressionMode(lz4hc) = %v, %v", got, err)
+	}
+	if _, err := ParseCompressionMode("Default"); !errors.Is(err, ErrInvalidCompressionOptions) {
+		t.Fatalf("ParseCompressionMode(Default) error = %v", err)
+	}
+	if got, err := ParseSwizzleProfile("normalmap_nohq"); err != nil || got != SwizzleProfileNormalMapNOHQ {
+		t.Fatalf("ParseSwizzleProfile(normalmap_nohq) = %v, %v", got, err)
+	}
+	if got, err := ParseColorSpace("SRGB"); err != nil || got != ColorSpaceSRGB {
+		t.Fatalf("ParseColorSpace(SRGB) = %v, %v", got, err)
+	}
+}
+
 func TestWorkbenchCorpus(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -653,11 +942,12 @@ func TestReadLimits(t *testing.T) {
 	if err := binary.Write(&blockTable, binary.LittleEndian, int32(1025)); err != nil {
 		t.Fatalf("write block size: %v", err)
 	}
-	_, _, _, err = readLargestMipFromBlocks(
+	_, _, _, err = readMipFromBlocks(
 		bytes.NewReader(blockTable.Bytes()),
 		&bcn.DDSHeader{Width: 4, Height: 4},
 		bcn.FormatDXT1,
 		1,
+		0,
 		readLimits{maxMipMaps: 1, maxBlockBytes: 1024, maxDecodedBytes: 1024},
 	)
 	if !errors.Is(err, ErrReadLimitExceeded) {
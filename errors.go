@@ -13,8 +13,20 @@ var (
 	ErrInvalidFormat = errors.New("invalid format")
 	// ErrInvalidSwizzleProfile indicates an unsupported swizzle profile.
 	ErrInvalidSwizzleProfile = errors.New("invalid swizzle profile")
-	// ErrUnsupportedTextureType indicates a texture is not a single 2D image.
+	// ErrInvalidMipFilter indicates an unsupported mipmap filter.
+	ErrInvalidMipFilter = errors.New("invalid mip filter")
+	// ErrInvalidNormalMap indicates unsupported normal map write options.
+	ErrInvalidNormalMap = errors.New("invalid normal map options")
+	// ErrInvalidColorSpace indicates a color space the output format cannot declare.
+	ErrInvalidColorSpace = errors.New("invalid color space")
+	// ErrUnsupportedTextureType indicates a texture layout the called API cannot read.
 	ErrUnsupportedTextureType = errors.New("unsupported texture type")
+	// ErrInvalidCubeTexture indicates cubemap faces that cannot be written.
+	ErrInvalidCubeTexture = errors.New("invalid cubemap texture")
+	// ErrInvalidTextureArray indicates texture array slices that cannot be written.
+	ErrInvalidTextureArray = errors.New("invalid texture array")
+	// ErrInvalidVolumeTexture indicates volume texture slices that cannot be written.
+	ErrInvalidVolumeTexture = errors.New("invalid volume texture")
 	// ErrEmptyMipmaps indicates missing mipmap data.
 	ErrEmptyMipmaps = errors.New("empty mipmaps")
 	// ErrMipmapSizeMismatch indicates mipmap payload size mismatch.
@@ -83,14 +95,36 @@ var (
 	ErrUnknownFormat = errors.New("unknown format")
 	// ErrDecompressBlock indicates block decompression failed.
 	ErrDecompressBlock = errors.New("decompress block failed")
-	// ErrLargestMipSizeMismatch indicates mismatch in largest mip size.
-	ErrLargestMipSizeMismatch = errors.New("largest mip size mismatch")
-	// ErrPickLargestMip indicates failure selecting largest mip.
-	ErrPickLargestMip = errors.New("failed to pick largest mip")
+	// ErrMipLevelOutOfRange indicates a requested mip level is not stored in the file.
+	ErrMipLevelOutOfRange = errors.New("mip level out of range")
+	// ErrMipSizeMismatch indicates a decoded mip level has the wrong size.
+	ErrMipSizeMismatch = errors.New("mip size mismatch")
+	// ErrPickMip indicates failure selecting the requested mip level.
+	ErrPickMip = errors.New("failed to pick mip")
+	// ErrLargestMipSizeMismatch is the former name of ErrMipSizeMismatch.
+	//
+	// Deprecated: use ErrMipSizeMismatch; both match with errors.Is.
+	ErrLargestMipSizeMismatch = ErrMipSizeMismatch
+	// ErrPickLargestMip is the former name of ErrPickMip.
+	//
+	// Deprecated: use ErrPickMip; both match with errors.Is.
+	ErrPickLargestMip = ErrPickMip
 	// ErrSeekDataStart indicates seek to data start failed.
 	ErrSeekDataStart = errors.New("seek to data start failed")
 	// ErrReadRemainingData indicates reading remaining data failed.
 	ErrReadRemainingData = errors.New("reading remaining data failed")
+	// ErrNotPlainDDS indicates ConvertDDS input that is already an EDDS file.
+	ErrNotPlainDDS = errors.New("input is not a plain DDS file")
+	// ErrTrailingData indicates bytes after the last mip of a plain DDS file.
+	ErrTrailingData = errors.New("trailing data after last mip")
+	// ErrBatchOutputConflict indicates two batch sources that map to the same output file.
+	ErrBatchOutputConflict = errors.New("batch output conflict")
+	// ErrInvalidManifest indicates a batch manifest that cannot be parsed.
+	ErrInvalidManifest = errors.New("invalid batch manifest")
+	// ErrRemoveStaleOutput indicates a failure to delete an output whose source disappeared.
+	ErrRemoveStaleOutput = errors.New("remove stale output failed")
+	// ErrLegacyMipmaps indicates a legacy single-block file whose header declares several mipmaps.
+	ErrLegacyMipmaps = errors.New("legacy single-block file declares mipmaps")
 	// ErrParseSingleBlock indicates failure parsing legacy single block.
 	ErrParseSingleBlock = errors.New("failed to parse single block")
 	// ErrCompressMipmap indicates mipmap compression failed.
@@ -103,6 +137,10 @@ var (
 	ErrWriteDDSMagic = errors.New("writing DDS magic failed")
 	// ErrWriteDDSHeader indicates DDS header write failed.
 	ErrWriteDDSHeader = errors.New("writing DDS header failed")
+	// ErrWriteDX10Header indicates DDS DX10 header write failed.
+	ErrWriteDX10Header = errors.New("writing DDS DX10 header failed")
+	// ErrWriteDDSData indicates writing exported DDS surface data failed.
+	ErrWriteDDSData = errors.New("writing DDS data failed")
 	// ErrWriteBlockMagic indicates block magic write failed.
 	ErrWriteBlockMagic = errors.New("writing block magic failed")
 	// ErrWriteBlockSize indicates block size write failed.
@@ -115,6 +153,8 @@ var (
 	ErrWriteChunkStream = errors.New("writing chunk stream failed")
 	// ErrWriteBlockPayload indicates block payload write failed.
 	ErrWriteBlockPayload = errors.New("writing block payload failed")
+	// ErrSeekOutput indicates seeking a streamed output to patch its block table failed.
+	ErrSeekOutput = errors.New("seeking output failed")
 	// ErrChunkHeaderRead indicates LZ4 chunk header read failed.
 	ErrChunkHeaderRead = errors.New("reading chunk header failed")
 	// ErrChunkDataRead indicates LZ4 chunk data read failed.
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// ExportOptions configures ExportDDS.
+type ExportOptions struct {
+	// KeepEnfusionMarker keeps the "ENF1" marker in DDS Reserved1.
+	// By default it is cleared so the output is a plain DDS file.
+	KeepEnfusionMarker bool
+	// Read limits the EDDS input. Nil uses default limits. Pixels are not
+	// decoded, so MaxImageBytes and Linearize do not apply.
+	Read *ReadOptions
+}
+
+// exportedDDS holds EDDS headers and decompressed mip blocks, largest first.
+type exportedDDS struct {
+	header bcn.DDSHeader
+	dx10   *bcn.DDSHeaderDX10
+	levels [][]byte
+	layout textureLayout
+}
+
+// ExportDDS converts an EDDS stream into a standard DDS stream without re-encoding pixels.
+// Every block is decompressed and written in DDS order: largest mip first,
+// with each cubemap face or array slice stored as a full mip chain.
+// Nil opts clears the ENF1 marker and uses default read limits.
+// The output is not streamed: DDS stores the largest mip first while EDDS
+// blocks arrive smallest first, so every decompressed level is held in memory.
+func ExportDDS(r io.Reader, w io.Writer, opts *ExportOptions) error {
+	dds, err := readExportedDDS(r, opts)
+	if err != nil {
+		return err
+	}
+
+	return dds.write(w)
+}
+
+// ExportDDSFile converts the EDDS file at src into the DDS file dst.
+// dst is replaced atomically and may be the same path as src.
+// Like ExportDDS it holds every decompressed level before writing.
+func ExportDDSFile(src, dst string, opts *ExportOptions) error {
+	limits, err := exportReadLimits(opts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrOpenFile, src, err)
+	}
+	if err := validateInputFileSize(f, limits); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	// Close src before replacing dst so in-place export works on every platform.
+	dds, err := readExportedDDS(f, opts)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(dst, func(f *os.File) error {
+		return dds.write(f)
+	})
+}
+
+// exportReadLimits returns the payload-only read limits of opts.
+func exportReadLimits(opts *ExportOptions) (readLimits, error) {
+	var read *ReadOptions
+	if opts != nil {
+		read = opts.Read
+	}
+	limits, err := normalizeReadLimits(read)
+	if err != nil {
+		return readLimits{}, err
+	}
+
+	return limits.forPayloads(), nil
+}
+
+// readExportedDDS reads and decompresses every mip block of an EDDS stream.
+func readExportedDDS(r io.Reader, opts *ExportOptions) (*exportedDDS, error) {
+	limits, err := exportReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var out exportedDDS
+	err = NewDecoder().readMipChain(r, limits, func(chain *mipChain) error {
+		if chain.legacy {
+			declared, err := readMipMapCount(chain.header, limits)
+			if err != nil {
+				return err
+			}
+			if declared != 1 {
+				return fmt.Errorf("%w: header declares %d mipmaps", ErrLegacyMipmaps, declared)
+			}
+		}
+
+		out.header = *chain.header
+		out.dx10 = chain.dx10
+		out.layout = chain.layout
+		out.levels = make([][]byte, chain.mipMapCount)
+		return nil
+	}, func(level, _, _ int, payload []byte) error {
+		// The Decoder reuses payload for the next level.
+		out.levels[level] = bytes.Clone(payload)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts == nil || !opts.KeepEnfusionMarker {
+		out.header.Reserved1[1] = 0
+	}
+
+	return &out, nil
+}
+
+// write writes the DDS headers followed by every surface chain, largest mip first.
+// Volume levels already hold their depth slices back to back, as DDS expects.
+func (x *exportedDDS) write(w io.Writer) error {
+	if err := writeDDSHeaders(w, &x.header, x.dx10); err != nil {
+		return err
+	}
+
+	chains := x.layout.faces * x.layout.arraySize
+	for chain := range chains {
+		for level, payload := range x.levels {
+			size := len(payload) / chains
+			if _, err := w.Write(payload[chain*size : (chain+1)*size]); err != nil {
+				return fmt.Errorf("%w: surface %d mipmap %d: %v", ErrWriteDDSData, chain, level, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestExportDDSLayout(t *testing.T) {
+	t.Parallel()
+
+	var src bytes.Buffer
+	if err := EncodeWithOptions(&src, hdrGradient(16), &WriteOptions{Format: bcn.FormatDXT5}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	payloads, err := DecodePayloads(bytes.NewReader(src.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodePayloads: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := ExportDDS(bytes.NewReader(src.Bytes()), &out, nil); err != nil {
+		t.Fatalf("ExportDDS: %v", err)
+	}
+
+	header, dx10, err := readEDDSHeaders(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("readEDDSHeaders: %v", err)
+	}
+	if dx10 != nil || header.Reserved1[1] != 0 || header.MipMapCount != 5 {
+		t.Fatalf("header reserved1[1] 0x%x, mipmaps %d, dx10 %v", header.Reserved1[1], header.MipMapCount, dx10)
+	}
+
+	// DDS stores mip data contiguously, largest first, right after the headers.
+	want := bytes.Join(payloads.Mipmaps, nil)
+	if got := out.Bytes()[4+bcn.DDSHeaderSize:]; !bytes.Equal(got, want) {
+		t.Fatalf("DDS data %d bytes differs from payloads (%d bytes)", len(got), len(want))
+	}
+
+	var kept bytes.Buffer
+	if err := ExportDDS(bytes.NewReader(src.Bytes()), &kept, &ExportOptions{KeepEnfusionMarker: true}); err != nil {
+		t.Fatalf("ExportDDS keep marker: %v", err)
+	}
+	info, err := Inspect(bytes.NewReader(kept.Bytes()))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !info.Enfusion {
+		t.Fatalf("ENF1 marker was not kept")
+	}
+}
+
+func TestExportDDSCubemapRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var cube CubeTexture
+	for face, c := range cubeFaceColors {
+		cube.Faces[face] = []image.Image{solidImage(8, c)}
+	}
+	dir := t.TempDir()
+	eddsPath := filepath.Join(dir, "sky.edds")
+	if err := WriteCube(&cube, eddsPath, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("WriteCube: %v", err)
+	}
+
+	ddsPath := filepath.Join(dir, "sky.dds")
+	if err := ExportDDSFile(eddsPath, ddsPath, nil); err != nil {
+		t.Fatalf("ExportDDSFile: %v", err)
+	}
+	if err := ConvertDDSFile(ddsPath, eddsPath, &ConvertOptions{Compression: CompressionOptions{Mode: CompressionLZ4}}); err != nil {
+		t.Fatalf("ConvertDDSFile: %v", err)
+	}
+
+	got, err := ReadCube(eddsPath, nil)
+	if err != nil {
+		t.Fatalf("ReadCube: %v", err)
+	}
+	for face, c := range cubeFaceColors {
+		for level, mip := range got.Faces[face] {
+			if px := mip.(*image.NRGBA).NRGBAAt(0, 0); px != c {
+				t.Fatalf("face %d level %d = %v, want %v", face, level, px, c)
+			}
+		}
+	}
+}
+
+func TestExportDDSReadLimits(t *testing.T) {
+	t.Parallel()
+
+	var src bytes.Buffer
+	if err := EncodeWithOptions(&src, hdrGradient(16), &WriteOptions{Format: bcn.FormatDXT5}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+
+	// Blocks are exported without decoding, so only payload limits apply.
+	opts := &ExportOptions{Read: &ReadOptions{MaxImageBytes: 16}}
+	if err := ExportDDS(bytes.NewReader(src.Bytes()), &bytes.Buffer{}, opts); err != nil {
+		t.Fatalf("ExportDDS with MaxImageBytes: %v", err)
+	}
+	opts.Read = &ReadOptions{MaxDecodedBytes: 256}
+	if err := ExportDDS(bytes.NewReader(src.Bytes()), &bytes.Buffer{}, opts); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("ExportDDS error = %v, want ErrReadLimitExceeded", err)
+	}
+}
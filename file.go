@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+
+	"github.com/woozymasta/bcn"
+)
+
+// File provides random access to the mip levels of an EDDS file.
+// Headers and the block table are parsed once by Open;
+// each level is then read with ReadAt at its precomputed offset.
+// File methods are safe for concurrent use when the underlying io.ReaderAt is.
+type File struct {
+	r             io.ReaderAt
+	info          *Info
+	decodeOptions *bcn.DecodeOptions
+	blockReaders  sync.Pool
+	limits        readLimits
+	linearize     bool
+}
+
+// Open parses the EDDS headers and block table from r.
+// size is the number of bytes available in r.
+func Open(r io.ReaderAt, size int64) (*File, error) {
+	return OpenWithOptions(r, size, nil)
+}
+
+// OpenWithOptions parses the EDDS headers and block table from r with the given options.
+// Read limits are checked for the table on open and for each level on access.
+func OpenWithOptions(r io.ReaderAt, size int64, opts *ReadOptions) (*File, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := inspect(io.NewSectionReader(r, 0, size), limits)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTextureType(&info.Header, info.DX10); err != nil {
+		return nil, err
+	}
+	for _, block := range info.Blocks {
+		if int64(block.Size) > int64(limits.maxBlockBytes) {
+			return nil, fmt.Errorf("%w: block %d size %d exceeds %d", ErrReadLimitExceeded, block.Index, block.Size, limits.maxBlockBytes)
+		}
+	}
+	if info.Legacy && info.Size-info.DataOffset > int64(limits.maxBlockBytes) {
+		return nil, fmt.Errorf("%w: legacy payload %d bytes exceeds %d", ErrReadLimitExceeded, info.Size-info.DataOffset, limits.maxBlockBytes)
+	}
+
+	f := &File{r: r, info: info, limits: limits.forColorSpace(info.DX10), linearize: linearizes(opts, info.DX10)}
+	if opts != nil {
+		f.decodeOptions = opts.DecodeOptions
+	}
+	f.blockReaders.New = func() any { return new(blockReader) }
+
+	return f, nil
+}
+
+// Info returns the parsed file structure. The result must not be modified.
+func (f *File) Info() *Info {
+	return f.info
+}
+
+// Format returns the detected texture format.
+func (f *File) Format() bcn.Format {
+	return f.info.Format
+}
+
+// Bounds returns the level 0 image bounds.
+func (f *File) Bounds() image.Rectangle {
+	return image.Rect(0, 0, f.info.Width, f.info.Height)
+}
+
+// NumLevels returns the number of stored mip levels.
+// Legacy single-block files store only level 0.
+func (f *File) NumLevels() int {
+	if f.info.Legacy {
+		return 1
+	}
+
+	return len(f.info.Blocks)
+}
+
+// RawBlock returns the stored block body of a mip level without decompression.
+// LZ4 blocks carry the chunk stream in Data and the stored size prefix in UncompressedSize,
+// matching the layout the writer emits. Legacy single-block files have no block table.
+func (f *File) RawBlock(level int) (*Block, error) {
+	if f.info.Legacy {
+		return nil, fmt.Errorf("%w: legacy single-block file has no block table", ErrReadBlockTable)
+	}
+	if err := validateMipLevel(level, uint32(len(f.info.Blocks))); err != nil {
+		return nil, err
+	}
+
+	block, err := f.readBlock(level)
+	if err != nil {
+		return nil, err
+	}
+	if block.Magic != BlockMagicLZ4 {
+		return block, nil
+	}
+
+	entry := f.blockInfo(level)
+	block.Data = block.Data[4:]
+	block.UncompressedSize = entry.UncompressedSize
+	return block, nil
+}
+
+// Payload returns the decompressed payload of a mip level in the stored pixel format.
+func (f *File) Payload(level int) ([]byte, error) {
+	payload, _, _, err := f.payload(level, f.limits.forPayloads())
+	return payload, err
+}
+
+// PayloadReader returns a reader over the decompressed payload of a mip level
+// in the stored pixel format. LZ4 blocks are inflated chunk by chunk as the
+// reader is consumed, so neither the compressed block nor the whole payload
+// is held in memory; copy it to a file to extract a huge level.
+// Errors match Payload. Legacy single-block files are decompressed up front.
+func (f *File) PayloadReader(level int) (io.Reader, error) {
+	if err := validateMipLevel(level, uint32(f.NumLevels())); err != nil {
+		return nil, err
+	}
+	if f.info.Legacy {
+		payload, _, _, err := f.payload(level, f.limits.forPayloads())
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(payload), nil
+	}
+
+	expectedSize, err := expectedReadDataLength(f.info.Format, mipDimension(f.info.Width, level), mipDimension(f.info.Height, level), f.limits.forPayloads())
+	if err != nil {
+		return nil, err
+	}
+	entry := f.blockInfo(level)
+	b := new(blockReader)
+	if err := b.reset(f.blockSection(entry), entry.Index, blockHeader{Magic: entry.Magic, Size: entry.Size}, expectedSize); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Image decodes a mip level into a new image.
+func (f *File) Image(level int) (image.Image, error) {
+	payload, width, height, err := f.payload(level, f.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := decodeImage(payload, width, height, f.info.Format, f.decodeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecodeImage, err)
+	}
+	if f.linearize {
+		img = linearizeImage(img)
+	}
+
+	return img, nil
+}
+
+// payload reads and decompresses one mip level within limits.
+func (f *File) payload(level int, limits readLimits) ([]byte, int, int, error) {
+	if err := validateMipLevel(level, uint32(f.NumLevels())); err != nil {
+		return nil, 0, 0, err
+	}
+
+	width := mipDimension(f.info.Width, level)
+	height := mipDimension(f.info.Height, level)
+	expectedSize, err := expectedReadDataLength(f.info.Format, width, height, limits)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if f.info.Legacy {
+		return readLegacySingleBlock(io.NewSectionReader(f.r, 0, f.info.Size), &f.info.Header, f.info.DX10, f.info.Format, limits)
+	}
+
+	b := f.blockReaders.Get().(*blockReader)
+	defer f.blockReaders.Put(b)
+
+	// Inflate straight from the file so the compressed block is never held whole.
+	entry := f.blockInfo(level)
+	payload, err := b.readPayload(nil, f.blockSection(entry), entry.Index, blockHeader{Magic: entry.Magic, Size: entry.Size}, expectedSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return payload, width, height, nil
+}
+
+// blockSection returns a reader over the stored body of a block.
+// Short bodies surface as a read error from the block reader.
+func (f *File) blockSection(entry *BlockInfo) io.Reader {
+	return io.NewSectionReader(f.r, entry.Offset, int64(entry.Size))
+}
+
+// readBlock reads the stored body of a mip level as the sequential reader does.
+func (f *File) readBlock(level int) (*Block, error) {
+	entry := f.blockInfo(level)
+	data := make([]byte, entry.Size)
+	// ReaderAt may report io.EOF with a full read when the block ends the file.
+	if n, err := f.r.ReadAt(data, entry.Offset); err != nil && (err != io.EOF || n != len(data)) {
+		return nil, fmt.Errorf("%w: mipmap %d: %v", ErrReadBlockBody, entry.Index, err)
+	}
+
+	return &Block{Magic: entry.Magic, Size: entry.Size, Data: data}, nil
+}
+
+// blockInfo returns the block-table entry for a mip level.
+func (f *File) blockInfo(level int) *BlockInfo {
+	return &f.info.Blocks[len(f.info.Blocks)-level-1]
+}
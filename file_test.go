@@ -0,0 +1,150 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestFileConcurrentLevels(t *testing.T) {
+	t.Parallel()
+
+	img := benchImage(64, 32)
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, img, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	want := bcn.GenerateMipmapsN(img, 0, false)
+
+	data := buf.Bytes()
+	f, err := Open(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if f.NumLevels() != len(want) || f.Bounds() != img.Bounds() {
+		t.Fatalf("File = %d levels %v, want %d levels %v", f.NumLevels(), f.Bounds(), len(want), img.Bounds())
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4*len(want))
+	for range 4 {
+		for level := range want {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				got, err := f.Image(level)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !bytes.Equal(got.(*image.NRGBA).Pix, want[level].Pix) {
+					errs <- errors.New("pixel mismatch")
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Image: %v", err)
+	}
+
+	for _, level := range []int{-1, len(want)} {
+		if _, err := f.Payload(level); !errors.Is(err, ErrMipLevelOutOfRange) {
+			t.Fatalf("Payload(%d) error = %v, want ErrMipLevelOutOfRange", level, err)
+		}
+	}
+}
+
+func TestFileRawBlockCorpus(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "corpus", "mip-grid-256-DXTCompression.edds"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	f, err := Open(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	for level := range f.NumLevels() {
+		block, err := f.RawBlock(level)
+		if err != nil {
+			t.Fatalf("RawBlock(%d): %v", level, err)
+		}
+
+		var stored bytes.Buffer
+		if err := writeBlockData(&stored, block); err != nil {
+			t.Fatalf("writeBlockData(%d): %v", level, err)
+		}
+		entry := f.Info().Blocks[len(f.Info().Blocks)-level-1]
+		if !bytes.Equal(stored.Bytes(), data[entry.Offset:entry.Offset+int64(entry.Size)]) {
+			t.Fatalf("level %d raw block does not round-trip", level)
+		}
+
+		payload, err := f.Payload(level)
+		if err != nil {
+			t.Fatalf("Payload(%d): %v", level, err)
+		}
+		if len(payload) != expectedDataLength(bcn.FormatDXT5, entry.Width, entry.Height) {
+			t.Fatalf("level %d payload %d bytes", level, len(payload))
+		}
+
+		r, err := f.PayloadReader(level)
+		if err != nil {
+			t.Fatalf("PayloadReader(%d): %v", level, err)
+		}
+		streamed, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(streamed, payload) {
+			t.Fatalf("level %d streamed payload differs: %v", level, err)
+		}
+	}
+}
+
+// eofReaderAt reports io.EOF together with a full read that reaches the end,
+// as io.ReaderAt allows.
+type eofReaderAt struct {
+	data []byte
+}
+
+func (r eofReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(r.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[off:])
+	if off+int64(n) == int64(len(r.data)) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func TestFileReaderAtEOFWithFullRead(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, benchImage(16, 16), &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	data := buf.Bytes()
+	f, err := Open(eofReaderAt{data: data}, int64(len(data)))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	// Level 0 is the last block, so its read ends exactly at EOF.
+	if _, err := f.RawBlock(0); err != nil {
+		t.Fatalf("RawBlock(0): %v", err)
+	}
+	if _, err := f.Image(0); err != nil {
+		t.Fatalf("Image(0): %v", err)
+	}
+}
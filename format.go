@@ -4,7 +4,12 @@
 
 package edds
 
-import "github.com/woozymasta/bcn"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/woozymasta/bcn"
+)
 
 // detectFormat detects the format of a DDS/EDDS file.
 func detectFormat(header *bcn.DDSHeader, dx10 *bcn.DDSHeaderDX10) bcn.Format {
@@ -73,6 +78,39 @@ func detectFormat(header *bcn.DDSHeader, dx10 *bcn.DDSHeaderDX10) bcn.Format {
 	return bcn.FormatUnknown
 }
 
+// ParseFormat returns the readable and writable format with the given name, ignoring case.
+// It accepts the names printed by FormatName and the DXT1, DXT3 and DXT5 aliases.
+func ParseFormat(name string) (bcn.Format, error) {
+	switch strings.ToUpper(name) {
+	case "DXT1":
+		return bcn.FormatDXT1, nil
+	case "DXT3":
+		return bcn.FormatDXT3, nil
+	case "DXT5":
+		return bcn.FormatDXT5, nil
+	}
+
+	for _, format := range supportedFormats() {
+		if strings.EqualFold(name, FormatName(format)) {
+			return format, nil
+		}
+	}
+
+	return bcn.FormatUnknown, fmt.Errorf("%w: %q", ErrInvalidFormat, name)
+}
+
+// supportedFormats lists every format edds can read and write.
+func supportedFormats() []bcn.Format {
+	formats := make([]bcn.Format, 0, 32)
+	for format := bcn.FormatUnknown + 1; format <= bcn.FormatBGR8; format++ {
+		if _, err := dxgiFormat(format); err == nil {
+			formats = append(formats, format)
+		}
+	}
+
+	return append(formats, FormatRGBA16F, FormatR32F, FormatR11G11B10F)
+}
+
 // mapDxgiFormat maps a DXGI format to a BCn format.
 func mapDxgiFormat(dxgiFormat uint32) bcn.Format {
 	switch dxgiFormat {
@@ -116,6 +154,16 @@ func mapDxgiFormat(dxgiFormat uint32) bcn.Format {
 		return bcn.FormatRGBA5551
 	case 115:
 		return bcn.FormatRGBA4444
+	case 95:
+		return bcn.FormatBC6HU
+	case 96:
+		return bcn.FormatBC6HS
+	case 10:
+		return FormatRGBA16F
+	case 41:
+		return FormatR32F
+	case 26:
+		return FormatR11G11B10F
 	default:
 		return bcn.FormatUnknown
 	}
@@ -154,9 +202,13 @@ func expectedDataLengthChecked(format bcn.Format, width, height int) (int, error
 	switch format {
 	case bcn.FormatDXT1, bcn.FormatBC4, bcn.FormatBC4S:
 		size = checkedDataLength((w+3)/4, (h+3)/4, 8)
-	case bcn.FormatDXT3, bcn.FormatDXT5, bcn.FormatBC5, bcn.FormatBC5S, bcn.FormatBC7:
+	case bcn.FormatDXT3, bcn.FormatDXT5, bcn.FormatBC5, bcn.FormatBC5S, bcn.FormatBC7,
+		bcn.FormatBC6HU, bcn.FormatBC6HS:
 		size = checkedDataLength((w+3)/4, (h+3)/4, 16)
-	case bcn.FormatRGBA8, bcn.FormatBGRA8, bcn.FormatBGRX8, bcn.FormatRGB10A2:
+	case FormatRGBA16F:
+		size = checkedDataLength(w, h, 8)
+	case bcn.FormatRGBA8, bcn.FormatBGRA8, bcn.FormatBGRX8, bcn.FormatRGB10A2,
+		FormatR32F, FormatR11G11B10F:
 		size = checkedDataLength(w, h, 4)
 	case bcn.FormatR8, bcn.FormatR8S, bcn.FormatA8:
 		size = checkedDataLength(w, h)
@@ -173,6 +225,18 @@ func expectedDataLengthChecked(format bcn.Format, width, height int) (int, error
 	return int(size), nil
 }
 
+// isBlockCompressed reports whether format stores 4x4 pixel blocks.
+func isBlockCompressed(format bcn.Format) bool {
+	switch format {
+	case bcn.FormatDXT1, bcn.FormatDXT3, bcn.FormatDXT5,
+		bcn.FormatBC4, bcn.FormatBC4S, bcn.FormatBC5, bcn.FormatBC5S,
+		bcn.FormatBC6HU, bcn.FormatBC6HS, bcn.FormatBC7:
+		return true
+	default:
+		return false
+	}
+}
+
 // checkedDataLength returns a positive byte length or zero on uint64 overflow.
 func checkedDataLength(factors ...uint64) uint64 {
 	product := uint64(1)
@@ -191,11 +255,14 @@ func makeFourCC(a, b, c, d byte) uint32 {
 	return uint32(a) | uint32(b)<<8 | uint32(c)<<16 | uint32(d)<<24
 }
 
+// enfusionMarker is the "ENF1" value Enfusion stores in DDS Reserved1[1].
+const enfusionMarker = 0x31464e45
+
 // enfusionReserved1 returns the reserved1 field value for Enfusion files.
 func enfusionReserved1() [11]uint32 {
 	return [11]uint32{
 		0,
-		0x31464e45, // "ENF1"
+		enfusionMarker,
 		0, 0, 0, 0, 0, 0, 0, 0, 0,
 	}
 }
@@ -222,7 +289,7 @@ func makeDDSHeader(width, height, mipMapCount uint32, format bcn.Format) (*bcn.D
 	}
 	hdr.PixelFormat.Size = bcn.DDSPixelFormatSize
 
-	// Keep legacy FourCC headers for BCn formats; EDDS does not need DX10 headers here.
+	// Prefer the legacy pixel formats Workbench writes; other formats fall back to DX10.
 	switch format {
 	case bcn.FormatDXT1:
 		hdr.Flags |= bcn.DDSFlagLinearSize
@@ -262,9 +329,184 @@ func makeDDSHeader(width, height, mipMapCount uint32, format bcn.Format) (*bcn.D
 		hdr.PixelFormat.BBitMask = 0x000000ff
 		hdr.PixelFormat.ABitMask = 0xff000000
 		hdr.PitchOrLinearSize = width * 4
+	case bcn.FormatBC4S:
+		hdr.Flags |= bcn.DDSFlagLinearSize
+		hdr.PixelFormat.Flags = bcn.DDSPFFourCC
+		hdr.PixelFormat.FourCC = makeFourCC('B', 'C', '4', 'S')
+	case bcn.FormatBC5S:
+		hdr.Flags |= bcn.DDSFlagLinearSize
+		hdr.PixelFormat.Flags = bcn.DDSPFFourCC
+		hdr.PixelFormat.FourCC = makeFourCC('B', 'C', '5', 'S')
+	case bcn.FormatR8:
+		// Workbench stores single-channel textures as 8-bit luminance.
+		hdr.Flags |= bcn.DDSFlagPitch
+		hdr.PixelFormat.Flags = bcn.DDSPFLuminance
+		hdr.PixelFormat.RGBBitCount = 8
+		hdr.PixelFormat.RBitMask = 0x000000ff
+		hdr.PitchOrLinearSize = width
+	case bcn.FormatRG8:
+		// Workbench stores two-channel textures as luminance plus alpha.
+		hdr.Flags |= bcn.DDSFlagPitch
+		hdr.PixelFormat.Flags = bcn.DDSPFLuminance | bcn.DDSPFAlphaPixels
+		hdr.PixelFormat.RGBBitCount = 16
+		hdr.PixelFormat.RBitMask = 0x000000ff
+		hdr.PixelFormat.ABitMask = 0x0000ff00
+		hdr.PitchOrLinearSize = width * 2
+	case bcn.FormatA8:
+		hdr.Flags |= bcn.DDSFlagPitch
+		hdr.PixelFormat.Flags = bcn.DDSPFAlpha
+		hdr.PixelFormat.RGBBitCount = 8
+		hdr.PixelFormat.ABitMask = 0x000000ff
+		hdr.PitchOrLinearSize = width
 	default:
-		return nil, ErrInvalidFormat
+		// Formats without a legacy pixel format use the "DX10" FourCC;
+		// makeDDSHeaders adds the DX10 header.
+		if _, err := dxgiFormat(format); err != nil {
+			return nil, err
+		}
+		hdr.PixelFormat.Flags = bcn.DDSPFFourCC
+		hdr.PixelFormat.FourCC = bcn.DDSFourCCDX10
+		if isBlockCompressed(format) {
+			hdr.Flags |= bcn.DDSFlagLinearSize
+			break
+		}
+
+		pitch, err := expectedDataLengthChecked(format, int(width), 1)
+		if err != nil {
+			return nil, err
+		}
+		hdr.Flags |= bcn.DDSFlagPitch
+		hdr.PitchOrLinearSize = uint32(pitch)
 	}
 
 	return hdr, nil
 }
+
+// dxgiFormat maps a writable format to its DXGI format code.
+// It is the inverse of mapDxgiFormat.
+func dxgiFormat(format bcn.Format) (uint32, error) {
+	switch format {
+	case bcn.FormatDXT1:
+		return 71, nil
+	case bcn.FormatDXT3:
+		return 74, nil
+	case bcn.FormatDXT5:
+		return 77, nil
+	case bcn.FormatBC4:
+		return 80, nil
+	case bcn.FormatBC4S:
+		return 81, nil
+	case bcn.FormatBC5:
+		return 83, nil
+	case bcn.FormatBC5S:
+		return 84, nil
+	case bcn.FormatBC7:
+		return 98, nil
+	case bcn.FormatRGBA8:
+		return 28, nil
+	case bcn.FormatBGRA8:
+		return 87, nil
+	case bcn.FormatBGRX8:
+		return 88, nil
+	case bcn.FormatRGB10A2:
+		return 24, nil
+	case bcn.FormatR8:
+		return 61, nil
+	case bcn.FormatR8S:
+		return 63, nil
+	case bcn.FormatRG8:
+		return 49, nil
+	case bcn.FormatRG8S:
+		return 51, nil
+	case bcn.FormatA8:
+		return 65, nil
+	case bcn.FormatRGB565:
+		return 85, nil
+	case bcn.FormatRGBA5551:
+		return 86, nil
+	case bcn.FormatRGBA4444:
+		return 115, nil
+	case bcn.FormatBC6HU:
+		return 95, nil
+	case bcn.FormatBC6HS:
+		return 96, nil
+	case FormatRGBA16F:
+		return 10, nil
+	case FormatR32F:
+		return 41, nil
+	case FormatR11G11B10F:
+		return 26, nil
+	default:
+		return 0, ErrInvalidFormat
+	}
+}
+
+// makeDDSHeaders extends makeDDSHeader with the caps of layout.
+// Arrays, volumes, formats without a legacy pixel format, and any explicit
+// color space also get a DX10 header; the base header then uses the "DX10" FourCC.
+func makeDDSHeaders(
+	width, height, mipMapCount uint32,
+	format bcn.Format,
+	colorSpace ColorSpace,
+	layout textureLayout,
+) (*bcn.DDSHeader, *bcn.DDSHeaderDX10, error) {
+	hdr, err := makeDDSHeader(width, height, mipMapCount, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if layout.faces == cubeFaceCount {
+		hdr.Caps |= bcn.DDSCapsComplex
+		hdr.Caps2 |= bcn.DDSCaps2Cubemap | ddsCaps2CubemapAllFaces
+	}
+	if layout.volume {
+		depth, err := u32FromInt(layout.depth)
+		if err != nil {
+			return nil, nil, err
+		}
+		hdr.Flags |= bcn.DDSFlagDepth
+		hdr.Depth = depth
+		hdr.Caps |= bcn.DDSCapsComplex
+		hdr.Caps2 |= ddsCaps2Volume
+	}
+	if !layout.needsDX10() && hdr.PixelFormat.FourCC != bcn.DDSFourCCDX10 && colorSpace == ColorSpaceUnspecified {
+		return hdr, nil, nil
+	}
+
+	var dxgi uint32
+	switch colorSpace {
+	case ColorSpaceUnspecified, ColorSpaceLinear:
+		dxgi, err = dxgiFormat(format)
+	case ColorSpaceSRGB:
+		dxgi, err = srgbDxgiFormat(format)
+	default:
+		err = fmt.Errorf("%w: %s", ErrInvalidColorSpace, colorSpace)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	arraySize, err := u32FromInt(layout.arraySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dx10 := &bcn.DDSHeaderDX10{
+		DXGIFormat:        dxgi,
+		ResourceDimension: dx10ResourceDimensionTexture2D,
+		ArraySize:         arraySize,
+	}
+	if layout.volume {
+		dx10.ResourceDimension = dx10ResourceDimensionTexture3D
+	}
+	if layout.faces == cubeFaceCount {
+		dx10.MiscFlag = dx10MiscTextureCube
+	}
+
+	hdr.PixelFormat = bcn.DDSPixelFormat{
+		Size:   bcn.DDSPixelFormatSize,
+		Flags:  bcn.DDSPFFourCC,
+		FourCC: bcn.DDSFourCCDX10,
+	}
+
+	return hdr, dx10, nil
+}
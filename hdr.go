@@ -0,0 +1,418 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/woozymasta/bcn"
+)
+
+// Float formats extend bcn.Format with uncompressed HDR layouts that bcn does not define.
+// Their values start at 0x100, far outside the bcn range. bcn cannot name,
+// encode or decode them, so edds routes them to its own code and never passes
+// them to bcn; use FormatName instead of bcn.Format.String to print them.
+const (
+	// FormatRGBA16F is DXGI R16G16B16A16_FLOAT (8 bytes per pixel).
+	FormatRGBA16F bcn.Format = formatFloatBase + iota
+	// FormatR32F is DXGI R32_FLOAT (4 bytes per pixel).
+	// Decoding replicates R to RGB and sets A to 1.
+	FormatR32F
+	// FormatR11G11B10F is DXGI R11G11B10_FLOAT (4 bytes per pixel, unsigned, no alpha).
+	FormatR11G11B10F
+)
+
+// formatFloatBase is the first edds float format value.
+const formatFloatBase bcn.Format = 0x100
+
+// bcnFormat guards calls into bcn: it rejects the edds float formats,
+// which bcn would treat as unknown, with an error that names them.
+func bcnFormat(format bcn.Format) error {
+	if format >= formatFloatBase {
+		return fmt.Errorf("%w: %s is not a bcn format", ErrInvalidFormat, FormatName(format))
+	}
+
+	return nil
+}
+
+// FormatName returns a display name for format, including the edds float formats.
+func FormatName(format bcn.Format) string {
+	switch format {
+	case FormatRGBA16F:
+		return "RGBA16F"
+	case FormatR32F:
+		return "R32F"
+	case FormatR11G11B10F:
+		return "R11G11B10F"
+	default:
+		return format.String()
+	}
+}
+
+// isHDRFormat reports whether format decodes to RGBAF32 instead of NRGBA.
+func isHDRFormat(format bcn.Format) bool {
+	switch format {
+	case bcn.FormatBC6HU, bcn.FormatBC6HS, FormatRGBA16F, FormatR32F, FormatR11G11B10F:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodedImageLength returns the in-memory size of a decoded image:
+// 4 bytes per pixel for NRGBA and 16 bytes per pixel for RGBAF32.
+func decodedImageLength(format bcn.Format, width, height int) (int, error) {
+	size, err := expectedDataLengthChecked(bcn.FormatRGBA8, width, height)
+	if err != nil || !isHDRFormat(format) {
+		return size, err
+	}
+	if size > maxInt/4 {
+		return 0, ErrSizeOverflow
+	}
+
+	return size * 4, nil
+}
+
+// RGBAF32 is an in-memory image of float32 RGBA pixels with straight alpha.
+// HDR formats decode to RGBAF32; values are linear and may exceed 1.
+// At clamps values to the 0..1 range of color.NRGBA64.
+type RGBAF32 struct {
+	// Pix holds R, G, B, A values in row-major order.
+	Pix []float32
+	// Stride is the number of Pix values between vertically adjacent pixels.
+	Stride int
+	// Rect is the image bounds.
+	Rect image.Rectangle
+}
+
+// NewRGBAF32 returns a new RGBAF32 image with the given bounds.
+func NewRGBAF32(r image.Rectangle) *RGBAF32 {
+	return &RGBAF32{
+		Pix:    make([]float32, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+// ColorModel returns color.NRGBA64Model.
+func (p *RGBAF32) ColorModel() color.Model {
+	return color.NRGBA64Model
+}
+
+// Bounds returns the image bounds.
+func (p *RGBAF32) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+// At returns the pixel at (x, y) clamped to color.NRGBA64.
+func (p *RGBAF32) At(x, y int) color.Color {
+	c := p.FloatAt(x, y)
+	return color.NRGBA64{R: unitToU16(c[0]), G: unitToU16(c[1]), B: unitToU16(c[2]), A: unitToU16(c[3])}
+}
+
+// PixOffset returns the index of the first Pix value of the pixel at (x, y).
+func (p *RGBAF32) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+// FloatAt returns the unclamped R, G, B, A values at (x, y).
+func (p *RGBAF32) FloatAt(x, y int) [4]float32 {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return [4]float32{}
+	}
+
+	i := p.PixOffset(x, y)
+	return [4]float32(p.Pix[i : i+4])
+}
+
+// SetFloat sets the R, G, B, A values at (x, y).
+func (p *RGBAF32) SetFloat(x, y int, c [4]float32) {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return
+	}
+
+	i := p.PixOffset(x, y)
+	copy(p.Pix[i:i+4], c[:])
+}
+
+// unitToU16 maps 0..1 to 0..65535 with clamping.
+func unitToU16(v float32) uint16 {
+	if !(v > 0) {
+		return 0
+	}
+	if v >= 1 {
+		return 0xffff
+	}
+
+	return uint16(v*0xffff + 0.5)
+}
+
+// toRGBAF32 returns img as a zero-origin *RGBAF32, converting when needed.
+func toRGBAF32(img image.Image) *RGBAF32 {
+	if f, ok := img.(*RGBAF32); ok && f.Rect.Min == (image.Point{}) {
+		return f
+	}
+
+	bounds := img.Bounds()
+	out := NewRGBAF32(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	if f, ok := img.(*RGBAF32); ok {
+		for y := range bounds.Dy() {
+			copy(out.Pix[y*out.Stride:(y+1)*out.Stride], f.Pix[f.PixOffset(bounds.Min.X, bounds.Min.Y+y):])
+		}
+		return out
+	}
+
+	for y := range bounds.Dy() {
+		for x := range bounds.Dx() {
+			c := color.NRGBA64Model.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA64)
+			i := out.PixOffset(x, y)
+			out.Pix[i] = float32(c.R) / 0xffff
+			out.Pix[i+1] = float32(c.G) / 0xffff
+			out.Pix[i+2] = float32(c.B) / 0xffff
+			out.Pix[i+3] = float32(c.A) / 0xffff
+		}
+	}
+
+	return out
+}
+
+// generateRGBAF32Mipmaps builds mipMapCount levels from base with filter;
+// the box filter averages 2x2 texels.
+func generateRGBAF32Mipmaps(base *RGBAF32, mipMapCount int, filter MipFilter) []*RGBAF32 {
+	if filter != MipFilterBox {
+		return filterRGBAF32Mipmaps(base, mipMapCount, filter)
+	}
+
+	mips := make([]*RGBAF32, 1, mipMapCount)
+	mips[0] = base
+	for level := 1; level < mipMapCount; level++ {
+		src := mips[level-1]
+		width := mipDimension(base.Rect.Dx(), level)
+		height := mipDimension(base.Rect.Dy(), level)
+		dst := NewRGBAF32(image.Rect(0, 0, width, height))
+		for y := range height {
+			for x := range width {
+				var sum [4]float32
+				n := float32(0)
+				for sy := 2 * y; sy < min(2*y+2, src.Rect.Dy()); sy++ {
+					for sx := 2 * x; sx < min(2*x+2, src.Rect.Dx()); sx++ {
+						i := src.PixOffset(sx, sy)
+						for c := range sum {
+							sum[c] += src.Pix[i+c]
+						}
+						n++
+					}
+				}
+				i := dst.PixOffset(x, y)
+				for c := range sum {
+					dst.Pix[i+c] = sum[c] / n
+				}
+			}
+		}
+		mips = append(mips, dst)
+	}
+
+	return mips
+}
+
+// decodeImage decodes one surface payload: HDR formats to *RGBAF32, others to *image.NRGBA.
+func decodeImage(data []byte, width, height int, format bcn.Format, opts *bcn.DecodeOptions) (image.Image, error) {
+	if isHDRFormat(format) {
+		return decodeHDR(data, width, height, format, opts)
+	}
+	if err := bcnFormat(format); err != nil {
+		return nil, err
+	}
+
+	return bcn.DecodeImageWithOptions(data, width, height, format, opts)
+}
+
+// decodeHDR decodes an HDR surface payload into a new RGBAF32 image.
+func decodeHDR(data []byte, width, height int, format bcn.Format, opts *bcn.DecodeOptions) (*RGBAF32, error) {
+	size, err := expectedDataLengthChecked(format, width, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < size {
+		return nil, fmt.Errorf("%w: %s payload %d bytes, want %d", ErrMipmapSizeMismatch, FormatName(format), len(data), size)
+	}
+
+	img := NewRGBAF32(image.Rect(0, 0, width, height))
+	pixels := width * height
+	switch format {
+	case bcn.FormatBC6HU, bcn.FormatBC6HS:
+		rgb, err := bcn.DecodeBC6HWithOptions(data, width, height, format == bcn.FormatBC6HS, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pixels {
+			img.Pix[4*i] = halfToFloat32(rgb[3*i])
+			img.Pix[4*i+1] = halfToFloat32(rgb[3*i+1])
+			img.Pix[4*i+2] = halfToFloat32(rgb[3*i+2])
+			img.Pix[4*i+3] = 1
+		}
+	case FormatRGBA16F:
+		for i := range 4 * pixels {
+			img.Pix[i] = halfToFloat32(binary.LittleEndian.Uint16(data[2*i:]))
+		}
+	case FormatR32F:
+		for i := range pixels {
+			v := math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+			img.Pix[4*i], img.Pix[4*i+1], img.Pix[4*i+2], img.Pix[4*i+3] = v, v, v, 1
+		}
+	case FormatR11G11B10F:
+		for i := range pixels {
+			packed := binary.LittleEndian.Uint32(data[4*i:])
+			img.Pix[4*i] = smallFloatToFloat32(packed&0x7ff, 6)
+			img.Pix[4*i+1] = smallFloatToFloat32((packed>>11)&0x7ff, 6)
+			img.Pix[4*i+2] = smallFloatToFloat32(packed>>22, 5)
+			img.Pix[4*i+3] = 1
+		}
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	return img, nil
+}
+
+// encodeHDR encodes a zero-origin RGBAF32 image into an HDR surface payload.
+func encodeHDR(img *RGBAF32, format bcn.Format, opts *bcn.EncodeOptions) ([]byte, error) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	pixels := width * height
+	switch format {
+	case bcn.FormatBC6HU, bcn.FormatBC6HS:
+		rgb := make([]uint16, 3*pixels)
+		for i := range pixels {
+			rgb[3*i] = float32ToHalf(img.Pix[4*i])
+			rgb[3*i+1] = float32ToHalf(img.Pix[4*i+1])
+			rgb[3*i+2] = float32ToHalf(img.Pix[4*i+2])
+		}
+		return bcn.EncodeBC6HWithOptions(rgb, width, height, format == bcn.FormatBC6HS, opts)
+	case FormatRGBA16F:
+		out := make([]byte, 8*pixels)
+		for i, v := range img.Pix[:4*pixels] {
+			binary.LittleEndian.PutUint16(out[2*i:], float32ToHalf(v))
+		}
+		return out, nil
+	case FormatR32F:
+		out := make([]byte, 4*pixels)
+		for i := range pixels {
+			binary.LittleEndian.PutUint32(out[4*i:], math.Float32bits(img.Pix[4*i]))
+		}
+		return out, nil
+	case FormatR11G11B10F:
+		out := make([]byte, 4*pixels)
+		for i := range pixels {
+			packed := float32ToSmallFloat(img.Pix[4*i], 6) |
+				float32ToSmallFloat(img.Pix[4*i+1], 6)<<11 |
+				float32ToSmallFloat(img.Pix[4*i+2], 5)<<22
+			binary.LittleEndian.PutUint32(out[4*i:], packed)
+		}
+		return out, nil
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// encodeHDRImages encodes every image with the HDR format of cfg.
+// Swizzle profiles operate on 8-bit channels and are rejected.
+func encodeHDRImages(mips []*RGBAF32, cfg *WriteOptions) ([][]byte, error) {
+	if cfg.SwizzleProfile != SwizzleProfileNone {
+		return nil, fmt.Errorf("%w: %d with %s", ErrInvalidSwizzleProfile, cfg.SwizzleProfile, FormatName(cfg.Format))
+	}
+
+	payloads := make([][]byte, len(mips))
+	err := forEachLevel(len(mips), cfg.Concurrency, func(_, i int) error {
+		data, err := encodeHDR(mips[i], cfg.Format, cfg.EncodeOptions)
+		if err != nil {
+			return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, i, err)
+		}
+		payloads[i] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payloads, nil
+}
+
+// halfToFloat32 converts an IEEE 754 half-precision value to float32.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+
+	switch {
+	case exp == 0:
+		// Zero or subnormal: mant * 2^-24.
+		v := float32(mant) / (1 << 24)
+		if sign != 0 {
+			v = -v
+		}
+		return v
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// float32ToHalf converts float32 to IEEE 754 half precision, rounding to nearest even.
+func float32ToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	abs := bits & 0x7fffffff
+
+	switch {
+	case abs > 0x7f800000:
+		return sign | 0x7e00 // NaN
+	case abs >= 0x477ff000:
+		return sign | 0x7c00 // rounds past 65504 to infinity
+	case abs < 0x38800000:
+		// Below the smallest normal half (2^-14): encode as subnormal.
+		return sign | uint16(math.RoundToEven(float64(math.Float32frombits(abs))*(1<<24)))
+	default:
+		h := (abs - (127-15)<<23) >> 13
+		rem := abs & 0x1fff
+		if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+			h++
+		}
+		return sign | uint16(h)
+	}
+}
+
+// float32ToSmallFloat converts f to an unsigned float with 5 exponent bits
+// and mantBits mantissa bits, as used by R11G11B10_FLOAT.
+// Negative values and NaN map to zero.
+func float32ToSmallFloat(f float32, mantBits uint) uint32 {
+	if !(f > 0) {
+		return 0
+	}
+
+	h := uint32(float32ToHalf(f))
+	if h >= 0x7c00 {
+		return 0x1f << mantBits // infinity
+	}
+
+	shift := 10 - mantBits
+	v := h >> shift
+	rem := h & (1<<shift - 1)
+	halfway := uint32(1) << (shift - 1)
+	if rem > halfway || (rem == halfway && v&1 == 1) {
+		v++
+	}
+
+	return v
+}
+
+// smallFloatToFloat32 converts an unsigned R11G11B10_FLOAT channel to float32.
+func smallFloatToFloat32(v uint32, mantBits uint) float32 {
+	return halfToFloat32(uint16(v << (10 - mantBits)))
+}
@@ -0,0 +1,170 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+// hdrGradient returns an RGBAF32 image with values up to 4 on red.
+func hdrGradient(size int) *RGBAF32 {
+	img := NewRGBAF32(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			img.SetFloat(x, y, [4]float32{4 * float32(x) / float32(size), float32(y) / float32(size), 0.25, 1})
+		}
+	}
+
+	return img
+}
+
+func TestHDRFormatsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		format    bcn.Format
+		dxgi      uint32
+		tolerance float64
+		gray      bool
+	}{
+		{format: FormatRGBA16F, dxgi: 10, tolerance: 0.004},
+		{format: FormatR32F, dxgi: 41, gray: true},
+		{format: FormatR11G11B10F, dxgi: 26, tolerance: 0.07},
+		{format: bcn.FormatBC6HU, dxgi: 95, tolerance: 0.25},
+		{format: bcn.FormatBC6HS, dxgi: 96, tolerance: 0.25},
+	}
+
+	for _, tc := range cases {
+		t.Run(FormatName(tc.format), func(t *testing.T) {
+			t.Parallel()
+
+			src := hdrGradient(16)
+			var buf bytes.Buffer
+			if err := EncodeWithOptions(&buf, src, &WriteOptions{Format: tc.format, Compress: true}); err != nil {
+				t.Fatalf("EncodeWithOptions: %v", err)
+			}
+
+			info, err := Inspect(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if info.DX10 == nil || info.DX10.DXGIFormat != tc.dxgi || info.Format != tc.format {
+				t.Fatalf("DX10 = %+v, format %d, want DXGI %d", info.DX10, info.Format, tc.dxgi)
+			}
+			if len(info.Blocks) != 5 {
+				t.Fatalf("blocks = %d, want 5", len(info.Blocks))
+			}
+
+			img, err := Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			got, ok := img.(*RGBAF32)
+			if !ok {
+				t.Fatalf("Decode returned %T, want *RGBAF32", img)
+			}
+
+			for _, p := range []image.Point{{0, 0}, {8, 4}, {15, 15}} {
+				want := src.FloatAt(p.X, p.Y)
+				if tc.gray {
+					want = [4]float32{want[0], want[0], want[0], 1}
+				}
+				c := got.FloatAt(p.X, p.Y)
+				for i := range c {
+					if math.Abs(float64(c[i]-want[i])) > tc.tolerance*math.Max(1, float64(want[i])) {
+						t.Fatalf("pixel %v = %v, want %v", p, c, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHDRWriteFileKeepsValuesAboveOne(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "sky.edds")
+	if err := WriteWithOptions(hdrGradient(8), path, &WriteOptions{Format: FormatRGBA16F, MaxMipMaps: 1}); err != nil {
+		t.Fatalf("WriteWithOptions: %v", err)
+	}
+
+	img, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	c := img.(*RGBAF32).FloatAt(7, 0)
+	if c[0] != 3.5 {
+		t.Fatalf("red = %v, want 3.5", c[0])
+	}
+	if r, _, _, _ := img.At(7, 0).RGBA(); r != 0xffff {
+		t.Fatalf("At clamped red = %d, want 65535", r)
+	}
+}
+
+func TestHDRRejectsSwizzle(t *testing.T) {
+	t.Parallel()
+
+	err := EncodeWithOptions(&bytes.Buffer{}, hdrGradient(4), &WriteOptions{
+		Format:         FormatRGBA16F,
+		SwizzleProfile: SwizzleProfileAlphaToRGB,
+	})
+	if !errors.Is(err, ErrInvalidSwizzleProfile) {
+		t.Fatalf("error = %v, want ErrInvalidSwizzleProfile", err)
+	}
+}
+
+func TestHalfFloatConversion(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []float32{0, 1, -2, 0.5, 65504, 1.0 / (1 << 24), 6.1035156e-05} {
+		if got := halfToFloat32(float32ToHalf(v)); got != v {
+			t.Fatalf("half round trip %v = %v", v, got)
+		}
+	}
+	if got := float32ToHalf(1e6); got != 0x7c00 {
+		t.Fatalf("overflow = 0x%x, want infinity", got)
+	}
+	if got := smallFloatToFloat32(float32ToSmallFloat(-1, 6), 6); got != 0 {
+		t.Fatalf("negative R11 = %v, want 0", got)
+	}
+}
+
+func TestFloatFormatsStayOutOfBCn(t *testing.T) {
+	t.Parallel()
+
+	// The last bcn format must stay below the edds float range.
+	if bcn.FormatBGR8 >= formatFloatBase {
+		t.Fatalf("bcn.FormatBGR8 = %d overlaps edds float formats at %d", bcn.FormatBGR8, formatFloatBase)
+	}
+
+	for _, format := range []bcn.Format{FormatRGBA16F, FormatR32F, FormatR11G11B10F} {
+		name := FormatName(format)
+		if name == format.String() {
+			t.Fatalf("FormatName(%d) = %q, want an edds name", format, name)
+		}
+		if got, err := ParseFormat(name); err != nil || got != format {
+			t.Fatalf("ParseFormat(%q) = %d, %v", name, got, err)
+		}
+
+		// Every bcn entry point rejects the format by its edds name.
+		err := bcnFormat(format)
+		if !errors.Is(err, ErrInvalidFormat) || !strings.Contains(err.Error(), name) {
+			t.Fatalf("bcnFormat(%s) = %v, want ErrInvalidFormat naming it", name, err)
+		}
+
+		// The edds paths decode and encode it.
+		var buf bytes.Buffer
+		if err := EncodeWithOptions(&buf, hdrGradient(8), &WriteOptions{Format: format}); err != nil {
+			t.Fatalf("EncodeWithOptions(%s): %v", name, err)
+		}
+		if _, err := NewDecoder().DecodeMip(bytes.NewReader(buf.Bytes()), 1, nil); err != nil {
+			t.Fatalf("DecodeMip(%s): %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/woozymasta/bcn"
+)
+
+// Info describes the structure of an EDDS file without decoded pixel data.
+type Info struct {
+	// DX10 is the DX10 extension header, or nil when the header has no DX10 FourCC.
+	DX10 *bcn.DDSHeaderDX10
+	// FourCC is the pixel format FourCC code, or empty for mask-based pixel formats.
+	FourCC string
+	// Blocks lists block-table entries in file order, from the smallest mip to the largest.
+	// It is empty for legacy single-block files.
+	Blocks []BlockInfo
+	// Header is the DDS header as stored in the file.
+	Header bcn.DDSHeader
+	// Size is the total input size in bytes.
+	Size int64
+	// DataOffset is the offset of the first block body or of the legacy payload,
+	// relative to the DDS magic.
+	DataOffset int64
+	// Format is the detected texture format; FormatUnknown when unsupported.
+	Format bcn.Format
+	// Width is the level 0 width in pixels.
+	Width int
+	// Height is the level 0 height in pixels.
+	Height int
+	// MipMapCount is the number of mip levels declared by the header (at least 1).
+	MipMapCount int
+	// DXGIFormat is the DX10 DXGI format code, or zero without a DX10 header.
+	DXGIFormat uint32
+	// ColorSpace is the color space declared by the DX10 header;
+	// ColorSpaceUnspecified for legacy headers.
+	ColorSpace ColorSpace
+	// Legacy reports an old file without a block table that stores one payload after the headers.
+	Legacy bool
+	// Enfusion reports whether the "ENF1" marker is present in DDS Reserved1.
+	Enfusion bool
+}
+
+// BlockInfo describes one EDDS block-table entry and its body.
+type BlockInfo struct {
+	// Magic is BlockMagicCOPY or BlockMagicLZ4.
+	Magic string
+	// Offset is the block body offset relative to the DDS magic.
+	Offset int64
+	// Index is the entry position in the block table.
+	Index int
+	// Level is the mip level stored in the block; 0 is the largest.
+	Level int
+	// Width is the mip width in pixels.
+	Width int
+	// Height is the mip height in pixels.
+	Height int
+	// Size is the stored body size from the block table.
+	Size int32
+	// UncompressedSize is the raw payload size: Size for COPY blocks
+	// and the stored size prefix for LZ4 blocks.
+	UncompressedSize int32
+}
+
+// Inspect parses EDDS headers and the block table without decompressing or decoding pixels.
+// Unlike Decode, it accepts any DDS resource type so lint tools can report it.
+func Inspect(r io.ReadSeeker) (*Info, error) {
+	limits, err := normalizeReadLimits(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return inspect(r, limits)
+}
+
+// inspect parses EDDS structure from r positioned at the DDS magic.
+func inspect(r io.ReadSeeker, limits readLimits) (*Info, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSeekDataStart, err)
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSeekDataStart, err)
+	}
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSeekDataStart, err)
+	}
+
+	header, dx10, err := readEDDSHeaders(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mipMapCount, err := readMipMapCount(header, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{
+		Header:      *header,
+		DX10:        dx10,
+		Size:        end - start,
+		Format:      detectFormat(header, dx10),
+		ColorSpace:  detectColorSpace(dx10),
+		Width:       int(header.Width),
+		Height:      int(header.Height),
+		MipMapCount: int(mipMapCount),
+		Enfusion:    header.Reserved1[1] == enfusionMarker,
+	}
+	if (header.PixelFormat.Flags & bcn.DDSPFFourCC) != 0 {
+		info.FourCC = intToFourCC(header.PixelFormat.FourCC)
+	}
+	if dx10 != nil {
+		info.DXGIFormat = dx10.DXGIFormat
+	}
+
+	hasBlockTable, err := hasBlockTableMagicAtCurrent(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadBlockTable, err)
+	}
+	if !hasBlockTable {
+		info.Legacy = true
+		info.DataOffset, err = r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSeekDataStart, err)
+		}
+		info.DataOffset -= start
+		return info, nil
+	}
+
+	table, err := readBlockTable(r, mipMapCount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadBlockTable, err)
+	}
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSeekDataStart, err)
+	}
+	info.DataOffset = offset - start
+
+	info.Blocks = make([]BlockInfo, len(table))
+	for i, h := range table {
+		level := len(table) - i - 1
+		if offset+int64(h.Size) > end {
+			return nil, fmt.Errorf("%w: mipmap %d: body ends at %d past input end %d",
+				ErrBlockBodyRead, i, offset+int64(h.Size)-start, end-start)
+		}
+
+		block := BlockInfo{
+			Magic:            h.Magic,
+			Offset:           offset - start,
+			Index:            i,
+			Level:            level,
+			Width:            mipDimension(info.Width, level),
+			Height:           mipDimension(info.Height, level),
+			Size:             h.Size,
+			UncompressedSize: h.Size,
+		}
+		if h.Magic == BlockMagicLZ4 {
+			if h.Size < 4 {
+				return nil, fmt.Errorf("%w: mipmap %d: LZ4 body %d bytes", ErrBlockBodyInvalidSize, i, h.Size)
+			}
+			if err := binary.Read(r, binary.LittleEndian, &block.UncompressedSize); err != nil {
+				return nil, fmt.Errorf("%w: mipmap %d: %v", ErrBlockBodyRead, i, err)
+			}
+		}
+
+		info.Blocks[i] = block
+		offset += int64(h.Size)
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("%w: mipmap %d: %v", ErrSkipBlockBody, i, err)
+		}
+	}
+
+	return info, nil
+}
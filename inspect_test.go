@@ -0,0 +1,84 @@
+package edds
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestInspectCorpus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file   string
+		fourCC string
+		dxgi   uint32
+		format bcn.Format
+	}{
+		{file: "mip-grid-256-Alpha.edds", format: bcn.FormatA8},
+		{file: "mip-grid-256-DXTCompression.edds", fourCC: "DXT5", format: bcn.FormatDXT5},
+		{file: "mip-grid-256-ColorHQCompression.edds", fourCC: "DX10", dxgi: 98, format: bcn.FormatBC7},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.file, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := os.Open(filepath.Join("testdata", "corpus", tc.file))
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer func() { _ = f.Close() }()
+
+			info, err := Inspect(f)
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if info.Format != tc.format || info.FourCC != tc.fourCC || info.DXGIFormat != tc.dxgi {
+				t.Fatalf("format = %v %q %d, want %v %q %d", info.Format, info.FourCC, info.DXGIFormat, tc.format, tc.fourCC, tc.dxgi)
+			}
+			if !info.Enfusion || info.Legacy || info.MipMapCount != 9 || len(info.Blocks) != 9 {
+				t.Fatalf("info = enfusion %v legacy %v mips %d blocks %d", info.Enfusion, info.Legacy, info.MipMapCount, len(info.Blocks))
+			}
+
+			offset := info.DataOffset
+			for i, block := range info.Blocks {
+				if block.Index != i || block.Level != 8-i || block.Offset != offset {
+					t.Fatalf("block %d = %+v, want level %d at %d", i, block, 8-i, offset)
+				}
+				want := expectedDataLength(tc.format, block.Width, block.Height)
+				if int(block.UncompressedSize) != want {
+					t.Fatalf("block %d uncompressed size = %d, want %d", i, block.UncompressedSize, want)
+				}
+				offset += int64(block.Size)
+			}
+			if offset != info.Size {
+				t.Fatalf("blocks end at %d, file size %d", offset, info.Size)
+			}
+		})
+	}
+}
+
+func TestInspectLegacySingleBlock(t *testing.T) {
+	t.Parallel()
+
+	header, err := makeDDSHeader(4, 4, 1, bcn.FormatBGRA8)
+	if err != nil {
+		t.Fatalf("makeDDSHeader: %v", err)
+	}
+	var buf bytes.Buffer
+	_ = bcn.WriteDDSMagic(&buf)
+	_ = bcn.WriteDDSHeader(&buf, header)
+	_, _ = buf.Write(make([]byte, 4*4*4))
+
+	info, err := Inspect(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !info.Legacy || len(info.Blocks) != 0 || info.DataOffset != 4+bcn.DDSHeaderSize {
+		t.Fatalf("legacy info = legacy %v blocks %d offset %d", info.Legacy, len(info.Blocks), info.DataOffset)
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+
+	"github.com/woozymasta/bcn"
+)
+
+const (
+	// ddsCaps2CubemapAllFaces combines the DDSCAPS2 flags of all six cubemap faces.
+	ddsCaps2CubemapAllFaces = 0xfc00
+	// ddsCaps2Volume marks a volume texture in DDSCAPS2.
+	ddsCaps2Volume = 0x200000
+
+	// cubeFaceCount is the number of faces in a cubemap.
+	cubeFaceCount = 6
+	// maxTextureSlices bounds array sizes and volume depth, matching the D3D11 limit.
+	maxTextureSlices = 2048
+)
+
+// textureLayout describes how many surfaces every EDDS mip block holds.
+// Surfaces of one level are stored back to back in a single block:
+// array slices in order, each holding its cubemap faces in DDS order,
+// or the depth slices of a volume level from front to back.
+type textureLayout struct {
+	faces     int  // 6 for cubemaps, otherwise 1
+	arraySize int  // DX10 array slices; 1 for non-array textures
+	depth     int  // level 0 depth of volume textures; 1 otherwise
+	volume    bool // 3D texture whose depth halves with every level
+}
+
+// texture2D is the layout of a plain 2D texture with one surface per level.
+var texture2D = textureLayout{faces: 1, arraySize: 1, depth: 1}
+
+// cubeLayout is the layout of a single cubemap.
+var cubeLayout = textureLayout{faces: cubeFaceCount, arraySize: 1, depth: 1}
+
+// surfaces returns the number of surfaces stored in the block of a mip level.
+func (l textureLayout) surfaces(level int) int {
+	return l.faces * l.arraySize * mipDimension(l.depth, level)
+}
+
+// needsDX10 reports whether the layout can only be described by a DX10 header.
+func (l textureLayout) needsDX10() bool {
+	return l.arraySize > 1 || l.volume
+}
+
+// readTextureLayout detects the texture layout declared by the DDS headers.
+func readTextureLayout(header *bcn.DDSHeader, dx10 *bcn.DDSHeaderDX10) (textureLayout, error) {
+	layout := texture2D
+	if (header.Caps2 & bcn.DDSCaps2Cubemap) != 0 {
+		if (header.Caps2 & ddsCaps2CubemapAllFaces) != ddsCaps2CubemapAllFaces {
+			return textureLayout{}, fmt.Errorf("%w: partial cubemap faces 0x%x", ErrUnsupportedTextureType, header.Caps2&ddsCaps2CubemapAllFaces)
+		}
+		layout.faces = cubeFaceCount
+	}
+	if (header.Caps2 & ddsCaps2Volume) != 0 {
+		layout.volume = true
+	}
+
+	if dx10 != nil {
+		switch dx10.ResourceDimension {
+		case dx10ResourceDimensionTexture2D:
+			if dx10.ArraySize == 0 || dx10.ArraySize > maxTextureSlices {
+				return textureLayout{}, fmt.Errorf("%w: DX10 array size %d", ErrUnsupportedTextureType, dx10.ArraySize)
+			}
+			layout.arraySize = int(dx10.ArraySize)
+			if (dx10.MiscFlag & dx10MiscTextureCube) != 0 {
+				layout.faces = cubeFaceCount
+			}
+		case dx10ResourceDimensionTexture3D:
+			if dx10.ArraySize != 1 {
+				return textureLayout{}, fmt.Errorf("%w: DX10 volume array size %d", ErrUnsupportedTextureType, dx10.ArraySize)
+			}
+			layout.volume = true
+		default:
+			return textureLayout{}, fmt.Errorf("%w: DX10 resource dimension %d", ErrUnsupportedTextureType, dx10.ResourceDimension)
+		}
+	}
+
+	if layout.volume {
+		if layout.faces != 1 || layout.arraySize != 1 {
+			return textureLayout{}, fmt.Errorf("%w: volume cubemaps and arrays", ErrUnsupportedTextureType)
+		}
+		if header.Depth > maxTextureSlices {
+			return textureLayout{}, fmt.Errorf("%w: volume depth %d", ErrUnsupportedTextureType, header.Depth)
+		}
+		layout.depth = max(1, int(header.Depth))
+	}
+
+	return layout, nil
+}
+
+// describe names the layout for error messages.
+func (l textureLayout) describe() string {
+	switch {
+	case l.volume:
+		return "volume texture"
+	case l.faces == cubeFaceCount && l.arraySize > 1:
+		return "cubemap array"
+	case l.faces == cubeFaceCount:
+		return "cubemap"
+	case l.arraySize > 1:
+		return "texture array"
+	default:
+		return "2D texture"
+	}
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+
+	"github.com/woozymasta/bcn"
+)
+
+// manifestVersion is bumped when entries written by older versions
+// can no longer prove an output is current; a mismatch rebuilds everything.
+const manifestVersion = 1
+
+// encoderRevision is bumped when this package writes different bytes for the
+// same source and options, so development builds without a module version
+// still invalidate outputs written by older code.
+const encoderRevision = 1
+
+// encoderModules are the modules whose versions decide the encoded bytes.
+var encoderModules = []string{
+	"github.com/woozymasta/edds",
+	"github.com/woozymasta/bcn",
+	"github.com/pierrec/lz4/v4",
+}
+
+// encoderVersion identifies the encoder code: encoderRevision plus the versions
+// of encoderModules recorded in the build info. It is part of every options
+// hash, so upgrading the library or the BCn encoder rebuilds incremental outputs.
+var encoderVersion = func() string {
+	version := fmt.Sprintf("edds-encoder/%d", encoderRevision)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, path := range encoderModules {
+		for _, module := range modules {
+			if module.Path != path {
+				continue
+			}
+			if module.Replace != nil {
+				module = module.Replace
+			}
+			version += fmt.Sprintf(" %s@%s", path, module.Version)
+			break
+		}
+	}
+
+	return version
+}()
+
+// batchManifest is the on-disk record of an incremental batch run.
+type batchManifest struct {
+	// Entries maps slash-separated source paths to their outputs.
+	Entries map[string]manifestEntry `json:"entries"`
+	// OutputRoot is the absolute output directory the entries are relative to.
+	OutputRoot string `json:"output_root"`
+	Version    int    `json:"version"`
+}
+
+// manifestEntry records the inputs that produced one output.
+type manifestEntry struct {
+	// Output is the slash-separated output path relative to the output root.
+	Output string `json:"output"`
+	// SourceHash is the SHA-256 of the source file. Empty forces a rebuild.
+	SourceHash string `json:"source_hash"`
+	// OptionsHash is the SHA-256 of the normalized write options.
+	OptionsHash string `json:"options_hash"`
+}
+
+// batchCache decides which batch sources can be skipped.
+type batchCache struct {
+	previous map[string]manifestEntry
+	options  string
+}
+
+// loadManifest reads the manifest at path for outputs below outputRoot.
+// A missing file, another manifest version, or another output root yields an
+// empty manifest, so every source is rebuilt and no previous output is deleted.
+func loadManifest(path, outputRoot string) (*batchManifest, error) {
+	manifest := &batchManifest{
+		Entries:    make(map[string]manifestEntry),
+		OutputRoot: outputRoot,
+		Version:    manifestVersion,
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+
+	var stored batchManifest
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidManifest, path, err)
+	}
+	if stored.Version == manifestVersion && stored.OutputRoot == outputRoot && stored.Entries != nil {
+		manifest.Entries = stored.Entries
+	}
+
+	return manifest, nil
+}
+
+// save writes the manifest atomically, creating its directory if needed.
+func (m *batchManifest) save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrCreateFile, path, err)
+	}
+
+	return writeFileAtomic(path, func(f *os.File) error {
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrCreateFile, path, err)
+		}
+		return nil
+	})
+}
+
+// removeStale deletes the outputs of previous entries whose source is gone,
+// unless another current source now writes the same output. Entries that are
+// kept or fail to delete are carried into next; with keep set nothing is deleted.
+func (m *batchManifest) removeStale(dstDir string, outputs map[string]string, next map[string]manifestEntry, keep bool, report *BatchReport) {
+	for source, entry := range m.Entries {
+		output := filepath.Join(dstDir, filepath.FromSlash(entry.Output))
+		if _, ok := outputs[output]; ok {
+			continue
+		}
+		if keep {
+			next[source] = entry
+			continue
+		}
+		if err := os.Remove(output); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			next[source] = entry
+			report.Failed = append(report.Failed, BatchResult{
+				Source: source,
+				Output: output,
+				Err:    fmt.Errorf("%w: %q: %v", ErrRemoveStaleOutput, output, err),
+			})
+			continue
+		}
+		report.Removed = append(report.Removed, output)
+	}
+	slices.Sort(report.Removed)
+}
+
+// writeOptionsHash identifies the output a normalized cfg produces
+// with the current encoder version.
+// Compression is hashed in its validated form so equivalent spellings
+// (Compress=true versus Mode=LZ4, MinRatio 0 versus the default) match,
+// and worker counts are ignored because they do not change the output.
+func writeOptionsHash(cfg WriteOptions, compression normalizedCompressionOptions) (string, error) {
+	return hashWriteOptions(cfg, compression, encoderVersion)
+}
+
+// hashWriteOptions is writeOptionsHash for the given encoder version.
+func hashWriteOptions(cfg WriteOptions, compression normalizedCompressionOptions, encoder string) (string, error) {
+	cfg.Compression = CompressionOptions{
+		Mode:      compression.mode,
+		HCLevel:   int(compression.hcLevel),
+		MinRatio:  compression.minRatio,
+		ChunkSize: compression.chunkSize,
+	}
+	cfg.Compress = compression.mode != CompressionNone
+	cfg.Concurrency = 0
+	if cfg.EncodeOptions != nil {
+		encodeOptions := *cfg.EncodeOptions
+		encodeOptions.Workers = 0
+		cfg.EncodeOptions = &encodeOptions
+		// Options equal to the zero value behave like nil ones.
+		got, gotErr := json.Marshal(encodeOptions)
+		zero, zeroErr := json.Marshal(bcn.EncodeOptions{})
+		if gotErr == nil && zeroErr == nil && bytes.Equal(got, zero) {
+			cfg.EncodeOptions = nil
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Encoder string       `json:"encoder"`
+		Options WriteOptions `json:"options"`
+	}{encoder, cfg})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	return sha256Hex(data), nil
+}
+
+// upToDate reports whether the previous run wrote result.Output from the same
+// source content and options, and the output still exists.
+func (c *batchCache) upToDate(result BatchResult) bool {
+	entry, ok := c.previous[result.Source]
+	if !ok || entry.SourceHash == "" || entry.SourceHash != result.hash || entry.OptionsHash != c.options {
+		return false
+	}
+	_, err := os.Stat(result.Output)
+
+	return err == nil
+}
+
+// sha256Hex returns the hex-encoded SHA-256 of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
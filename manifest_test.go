@@ -0,0 +1,185 @@
+package edds
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestConvertDirIncremental(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	dst := t.TempDir()
+	manifest := filepath.Join(t.TempDir(), "cache", "manifest.json")
+	write := func(name string, size int) {
+		t.Helper()
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(path, batchPNG(t, size), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	run := func(opts *WriteOptions) *BatchReport {
+		t.Helper()
+		report, err := ConvertDir(context.Background(), src, dst, &BatchOptions{
+			WriteOptions: opts,
+			Manifest:     manifest,
+			Workers:      2,
+		})
+		if err != nil {
+			t.Fatalf("ConvertDir: %v", err)
+		}
+		return report
+	}
+
+	write("a.png", 8)
+	write("sub/b.png", 8)
+	write("sub/c.png", 4)
+	if report := run(nil); report.Converted != 3 || report.Skipped != 0 {
+		t.Fatalf("first run = %+v", report)
+	}
+
+	// Unchanged inputs and options are skipped; equivalent option spellings match.
+	same := &WriteOptions{Compression: CompressionOptions{Mode: CompressionLZ4}, EncodeOptions: &bcn.EncodeOptions{Workers: 3}, Concurrency: 4}
+	if report := run(same); report.Converted != 0 || report.Skipped != 3 {
+		t.Fatalf("unchanged run = %+v", report)
+	}
+
+	// A changed input, a missing output, and a removed source.
+	write("a.png", 16)
+	if err := os.Remove(filepath.Join(dst, "sub", "b.edds")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := os.Remove(filepath.Join(src, "sub", "c.png")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	report := run(nil)
+	if report.Converted != 2 || report.Skipped != 0 || report.Total != 2 ||
+		len(report.Removed) != 1 || report.Removed[0] != filepath.Join(dst, "sub", "c.edds") {
+		t.Fatalf("changed run = %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "sub", "c.edds")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("stale output still exists: %v", err)
+	}
+	img, err := Read(filepath.Join(dst, "a.edds"))
+	if err != nil || img.Bounds().Dx() != 16 {
+		t.Fatalf("rebuilt a.edds: %v, %v", img, err)
+	}
+
+	// Different write options rebuild everything.
+	if report := run(&WriteOptions{Format: bcn.FormatDXT1}); report.Converted != 2 || report.Skipped != 0 {
+		t.Fatalf("options run = %+v", report)
+	}
+	if report := run(&WriteOptions{Format: bcn.FormatDXT1}); report.Skipped != 2 {
+		t.Fatalf("options rerun = %+v", report)
+	}
+}
+
+func TestConvertDirIncrementalFailures(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	dst := t.TempDir()
+	manifest := filepath.Join(dst, "manifest.json")
+	bad := filepath.Join(src, "bad.png")
+	if err := os.WriteFile(bad, []byte("junk"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	for range 2 {
+		report, err := ConvertDir(context.Background(), src, dst, &BatchOptions{Manifest: manifest})
+		if err != nil {
+			t.Fatalf("ConvertDir: %v", err)
+		}
+		if len(report.Failed) != 1 || report.Skipped != 0 {
+			t.Fatalf("failed file must be retried: %+v", report)
+		}
+	}
+
+	if err := os.WriteFile(manifest, []byte("{"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := ConvertDir(context.Background(), src, dst, &BatchOptions{Manifest: manifest}); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("corrupt manifest error = %v", err)
+	}
+
+	// Another manifest version rebuilds instead of failing.
+	if err := os.WriteFile(manifest, []byte(`{"version": 0, "entries": {}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := ConvertDir(context.Background(), src, dst, &BatchOptions{Manifest: manifest}); err != nil {
+		t.Fatalf("old manifest version: %v", err)
+	}
+}
+
+func TestConvertDirManifestOutputRoot(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	first := t.TempDir()
+	second := t.TempDir()
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	for _, name := range []string{"a.png", "b.png"} {
+		if err := os.WriteFile(filepath.Join(src, name), batchPNG(t, 4), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	if _, err := ConvertDir(context.Background(), src, first, &BatchOptions{Manifest: manifest}); err != nil {
+		t.Fatalf("ConvertDir first: %v", err)
+	}
+
+	// The same manifest for another output root rebuilds everything and deletes nothing,
+	// neither below the new root nor below the old one.
+	if err := os.Remove(filepath.Join(src, "b.png")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(second, "b.edds"), []byte("keep"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	report, err := ConvertDir(context.Background(), src, second, &BatchOptions{Manifest: manifest})
+	if err != nil {
+		t.Fatalf("ConvertDir second: %v", err)
+	}
+	if report.Converted != 1 || report.Skipped != 0 || len(report.Removed) != 0 {
+		t.Fatalf("other root run = %+v", report)
+	}
+	for _, path := range []string{filepath.Join(second, "b.edds"), filepath.Join(first, "b.edds")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("output %s was deleted: %v", path, err)
+		}
+	}
+}
+
+func TestWriteOptionsHashEncoderVersion(t *testing.T) {
+	t.Parallel()
+
+	if !strings.Contains(encoderVersion, "github.com/woozymasta/bcn@") {
+		t.Fatalf("encoderVersion = %q, want the bcn module version", encoderVersion)
+	}
+
+	cfg := normalizeWriteOptions(&WriteOptions{Format: bcn.FormatBC7})
+	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
+	if err != nil {
+		t.Fatalf("normalizeCompressionOptions: %v", err)
+	}
+	current, err := writeOptionsHash(cfg, compression)
+	if err != nil {
+		t.Fatalf("writeOptionsHash: %v", err)
+	}
+	upgraded, err := hashWriteOptions(cfg, compression, encoderVersion+" github.com/woozymasta/bcn@v99.0.0")
+	if err != nil {
+		t.Fatalf("hashWriteOptions: %v", err)
+	}
+	// An encoder upgrade changes the hash, so incremental runs rebuild every output.
+	if current == upgraded {
+		t.Fatal("options hash ignores the encoder version")
+	}
+}
@@ -0,0 +1,328 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/woozymasta/bcn"
+)
+
+// mipChain describes the parsed headers of an EDDS stream walked level by level.
+type mipChain struct {
+	header      *bcn.DDSHeader
+	dx10        *bcn.DDSHeaderDX10
+	format      bcn.Format
+	mipMapCount uint32 // stored levels; 1 for legacy single-block input
+	layout      textureLayout
+	legacy      bool
+}
+
+// mipVisitor receives one decompressed mip payload.
+// width and height are per surface; payload holds every surface of the level back to back.
+// payload is owned by the Decoder and is only valid until visit returns.
+type mipVisitor func(level, width, height int, payload []byte) error
+
+// ReadAll reads and decodes every stored mip level of an EDDS file.
+// Images are ordered from largest (level 0) to smallest.
+func ReadAll(path string, opts *ReadOptions) ([]image.Image, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := validateInputFileSize(f, limits); err != nil {
+		return nil, err
+	}
+
+	return NewDecoder().DecodeAll(f, opts)
+}
+
+// DecodeAll reads and decodes every stored mip level of an EDDS stream.
+// Images are ordered from largest (level 0) to smallest.
+func DecodeAll(r io.Reader, opts *ReadOptions) ([]image.Image, error) {
+	return NewDecoder().DecodeAll(r, opts)
+}
+
+// DecodeAll reads and decodes every stored mip level of an EDDS stream.
+// Images are ordered from largest (level 0) to smallest.
+// Unlike Decode, returned images own their pixel buffers and stay valid
+// after later calls on the same Decoder.
+// ReadOptions limits apply to every level and to the sum across levels.
+func (d *Decoder) DecodeAll(r io.Reader, opts *ReadOptions) ([]image.Image, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	decOpts := (*bcn.DecodeOptions)(nil)
+	if opts != nil {
+		decOpts = opts.DecodeOptions
+	}
+
+	var images []image.Image
+	var format bcn.Format
+	var linearize bool
+	err = d.readMipChain(r, limits, func(chain *mipChain) error {
+		if err := validateTextureType(chain.header, chain.dx10); err != nil {
+			return err
+		}
+		format = chain.format
+		linearize = linearizes(opts, chain.dx10)
+		images = make([]image.Image, chain.mipMapCount)
+		return nil
+	}, func(level, width, height int, payload []byte) error {
+		img, err := decodeImage(payload, width, height, format, decOpts)
+		if err != nil {
+			return fmt.Errorf("%w: mipmap %d: %v", ErrDecodeImage, level, err)
+		}
+		if linearize {
+			img = linearizeImage(img)
+		}
+		images[level] = img
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// readMipChain parses the EDDS headers from r and passes every stored mip payload to visit.
+// start is called once after the headers are parsed and limits are validated.
+// Block bodies are visited in file order, from the smallest to the largest mip.
+func (d *Decoder) readMipChain(
+	r io.Reader,
+	limits readLimits,
+	start func(chain *mipChain) error,
+	visit mipVisitor,
+) error {
+	stream := bufio.NewReader(&limitedReader{r: r, remaining: limits.maxInputBytes})
+
+	header, dx10, err := readEDDSHeaders(stream)
+	if err != nil {
+		return err
+	}
+	layout, err := readTextureLayout(header, dx10)
+	if err != nil {
+		return err
+	}
+	limits = limits.forColorSpace(dx10)
+
+	chain := &mipChain{header: header, dx10: dx10, format: detectFormat(header, dx10), layout: layout}
+	chain.mipMapCount, err = readMipMapCount(header, limits)
+	if err != nil {
+		return err
+	}
+
+	hasBlockTable, err := hasBlockTableMagic(stream)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrReadBlockTable, err)
+	}
+	if !hasBlockTable {
+		if layout != texture2D {
+			return fmt.Errorf("%w: legacy single-block %s", ErrUnsupportedTextureType, layout.describe())
+		}
+		chain.legacy = true
+		chain.mipMapCount = 1
+	}
+
+	if err := validateMipChainLimits(chain.format, int(header.Width), int(header.Height), chain.mipMapCount, layout, limits); err != nil {
+		return err
+	}
+	if err := start(chain); err != nil {
+		return err
+	}
+
+	if chain.legacy {
+		payload, width, height, err := d.readLegacySingleBlockFromReader(stream, header, chain.format, limits)
+		if err != nil {
+			return err
+		}
+		return visit(0, width, height, payload)
+	}
+
+	table, err := readBlockTableInto(d.blockTable, stream, chain.mipMapCount)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReadBlockTable, err)
+	}
+	d.blockTable = table
+	if err := validateBlockTable(table, limits); err != nil {
+		return err
+	}
+	if limits.workers > 1 && len(table) > 1 {
+		return d.readBlocksParallel(stream, chain, table, limits, visit)
+	}
+
+	// EDDS writes the block table and payloads from smallest to largest mip.
+	for i := range chain.mipMapCount {
+		level := int(chain.mipMapCount - i - 1)
+		width := mipDimension(int(header.Width), level)
+		height := mipDimension(int(header.Height), level)
+		surfaceSize, err := expectedReadDataLength(chain.format, width, height, limits)
+		if err != nil {
+			return err
+		}
+		// validateMipChainLimits already bounded the sum over all surfaces.
+		expectedSize := surfaceSize * layout.surfaces(level)
+
+		decompressed, err := d.blocks.readPayload(d.raw, stream, int(i), table[i], expectedSize)
+		if err != nil {
+			return err
+		}
+		d.raw = decompressed
+
+		if err := visit(level, width, height, decompressed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readBlocksParallel reads the block bodies of chain in file order and
+// decompresses them on up to limits.workers goroutines, then visits the
+// payloads in file order. Errors match the serial loop: the first failing block
+// in file order wins. Each worker owns one body buffer and the reader only fills
+// a free one, so at most limits.workers bodies of up to maxBlockBytes are held
+// at once; payloads are bounded by validateMipChainLimits.
+func (d *Decoder) readBlocksParallel(
+	r io.Reader,
+	chain *mipChain,
+	table []blockHeader,
+	limits readLimits,
+	visit mipVisitor,
+) error {
+	count := len(table)
+	workers := max(1, min(limits.workers, count))
+	if len(d.decompressors) < workers {
+		d.decompressors = append(d.decompressors, make([]blockDecompressor, workers-len(d.decompressors))...)
+	}
+	d.blockBodies = ensurePayloadSlots(d.blockBodies, workers)
+	d.raws = ensurePayloadSlots(d.raws, count)
+	errs := make([]error, count)
+
+	type blockJob struct {
+		block *Block
+		index int
+		slot  int
+		size  int
+	}
+	free := make(chan int, workers)
+	for slot := range workers {
+		free <- slot
+	}
+	jobs := make(chan blockJob)
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Go(func() {
+			for job := range jobs {
+				decompressed, err := d.decompressors[worker].decompressBlock(d.raws[job.index], job.block, job.size)
+				switch {
+				case err != nil:
+					errs[job.index] = fmt.Errorf("%w: mipmap %d: %v", ErrDecompressBlock, job.index, err)
+				case len(decompressed) != job.size:
+					errs[job.index] = fmt.Errorf("%w: mipmap %d: expected %d, got %d", ErrMipSizeMismatch, job.index, job.size, len(decompressed))
+				default:
+					d.raws[job.index] = decompressed
+				}
+				free <- job.slot
+			}
+		})
+	}
+
+	// A read failure ends the stream; blocks before it still report their errors first.
+	var readErr error
+	read := 0
+	for i := range count {
+		level := count - i - 1
+		width := mipDimension(int(chain.header.Width), level)
+		height := mipDimension(int(chain.header.Height), level)
+		surfaceSize, err := expectedReadDataLength(chain.format, width, height, limits)
+		if err != nil {
+			readErr = err
+			break
+		}
+
+		slot := <-free
+		block, data, err := readBlockBodyInto(d.blockBodies[slot], r, table[i])
+		d.blockBodies[slot] = data
+		if err != nil {
+			readErr = fmt.Errorf("%w: mipmap %d: %v", ErrReadBlockBody, i, err)
+			break
+		}
+		jobs <- blockJob{block: block, index: i, slot: slot, size: surfaceSize * chain.layout.surfaces(level)}
+		read = i + 1
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range read {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		level := count - i - 1
+		width := mipDimension(int(chain.header.Width), level)
+		height := mipDimension(int(chain.header.Height), level)
+		if err := visit(level, width, height, d.raws[i]); err != nil {
+			return err
+		}
+	}
+
+	return readErr
+}
+
+// validateMipChainLimits checks decoded payload and image sizes summed over a whole mip chain
+// and every surface of the layout.
+// Per-level limits are checked separately by expectedReadDataLength.
+func validateMipChainLimits(
+	format bcn.Format,
+	width, height int,
+	mipMapCount uint32,
+	layout textureLayout,
+	limits readLimits,
+) error {
+	var payloadTotal, imageTotal uint64
+	for level := range int(mipMapCount) {
+		mipW := mipDimension(width, level)
+		mipH := mipDimension(height, level)
+
+		imageSize, err := limits.imageLength(format, mipW, mipH)
+		if err != nil {
+			return err
+		}
+		size, err := expectedDataLengthChecked(format, mipW, mipH)
+		if err != nil {
+			if err == ErrInvalidFormat {
+				return fmt.Errorf("%w: %s", ErrUnknownFormat, FormatName(format))
+			}
+			return err
+		}
+
+		surfaces := uint64(layout.surfaces(level))
+		imageTotal += uint64(imageSize) * surfaces
+		payloadTotal += uint64(size) * surfaces
+	}
+
+	if imageTotal > uint64(limits.maxImageBytes) {
+		return fmt.Errorf("%w: decoded mip chain images %d bytes exceeds %d", ErrReadLimitExceeded, imageTotal, limits.maxImageBytes)
+	}
+	if payloadTotal > uint64(limits.maxDecodedBytes) {
+		return fmt.Errorf("%w: decoded mip chain %d bytes exceeds %d", ErrReadLimitExceeded, payloadTotal, limits.maxDecodedBytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestDecodeAll(t *testing.T) {
+	t.Parallel()
+
+	img := benchImage(32, 16)
+	var buf bytes.Buffer
+	if err := EncodeWithOptions(&buf, img, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	want := bcn.GenerateMipmapsN(img, 0, false)
+
+	got, err := DecodeAll(&maxReadRequestReader{r: bytes.NewReader(buf.Bytes()), max: 64 * 1024}, nil)
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DecodeAll returned %d levels, want %d", len(got), len(want))
+	}
+	for level := range want {
+		gotNRGBA, ok := got[level].(*image.NRGBA)
+		if !ok {
+			t.Fatalf("level %d: expected *image.NRGBA, got %T", level, got[level])
+		}
+		if gotNRGBA.Bounds() != want[level].Bounds() || !bytes.Equal(gotNRGBA.Pix, want[level].Pix) {
+			t.Fatalf("level %d mismatch", level)
+		}
+	}
+
+	// Level 0 fits the image limit on its own, but the whole chain does not.
+	_, err = DecodeAll(bytes.NewReader(buf.Bytes()), &ReadOptions{MaxImageBytes: 32 * 16 * 4})
+	if !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("total limit error = %v, want ErrReadLimitExceeded", err)
+	}
+}
+
+func TestReadAllCorpus(t *testing.T) {
+	t.Parallel()
+
+	images, err := ReadAll(filepath.Join("testdata", "corpus", "mip-grid-256-DXTCompression.edds"), nil)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(images) != 9 {
+		t.Fatalf("ReadAll returned %d levels, want 9", len(images))
+	}
+	for level, img := range images {
+		size := 256 >> level
+		if img.Bounds() != image.Rect(0, 0, size, size) {
+			t.Fatalf("level %d bounds = %v, want %dx%d", level, img.Bounds(), size, size)
+		}
+	}
+}
+
+func TestDecodeAllConcurrency(t *testing.T) {
+	t.Parallel()
+
+	// The smooth left half keeps the large blocks LZ4, the noisy right half keeps them distinct.
+	img := benchImage(64, 32)
+	for y := range 32 {
+		for x := range 32 {
+			img.SetNRGBA(x, y, color.NRGBA{R: 40, G: 80, B: 120, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	opts := &WriteOptions{Format: bcn.FormatBGRA8, Compression: CompressionOptions{Mode: CompressionLZ4}}
+	if err := EncodeWithOptions(&buf, img, opts); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	data := buf.Bytes()
+
+	want, err := DecodePayloads(bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("DecodePayloads serial: %v", err)
+	}
+	// One decoder across runs also checks that its per-block buffers are reused correctly.
+	dec := NewDecoder()
+	for _, concurrency := range []int{2, 64, 3} {
+		opts := &ReadOptions{Concurrency: concurrency}
+		got, err := dec.DecodePayloads(bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf("concurrency %d: DecodePayloads: %v", concurrency, err)
+		}
+		for level := range want.Mipmaps {
+			if !bytes.Equal(got.Mipmaps[level], want.Mipmaps[level]) {
+				t.Fatalf("concurrency %d: level %d payload differs", concurrency, level)
+			}
+		}
+
+		images, err := dec.DecodeAll(bytes.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf("concurrency %d: DecodeAll: %v", concurrency, err)
+		}
+		if len(images) != len(want.Mipmaps) || images[0].Bounds() != img.Bounds() {
+			t.Fatalf("concurrency %d: DecodeAll returned %d levels", concurrency, len(images))
+		}
+		// Compressed bodies are held one per worker, not one per block.
+		if len(dec.blockBodies) != min(concurrency, len(want.Mipmaps)) {
+			t.Fatalf("concurrency %d: %d block bodies held", concurrency, len(dec.blockBodies))
+		}
+	}
+
+	// Corrupt and truncated input must fail with the serial error.
+	info, err := Inspect(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	middle := info.Blocks[len(info.Blocks)/2]
+	largest := info.Blocks[len(info.Blocks)-1]
+	if largest.Magic != BlockMagicLZ4 {
+		t.Fatalf("largest block magic = %q, want LZ4", largest.Magic)
+	}
+	corrupt := bytes.Clone(data)
+	for i := range largest.Size - 4 {
+		corrupt[largest.Offset+4+int64(i)] = 0xff
+	}
+	for name, input := range map[string][]byte{
+		"corrupt":   corrupt,
+		"truncated": data[:middle.Offset+int64(middle.Size)/2],
+	} {
+		_, serialErr := DecodeAll(bytes.NewReader(input), nil)
+		_, parallelErr := DecodeAll(bytes.NewReader(input), &ReadOptions{Concurrency: 4})
+		if serialErr == nil || parallelErr == nil || serialErr.Error() != parallelErr.Error() {
+			t.Fatalf("%s: parallel error %v, want %v", name, parallelErr, serialErr)
+		}
+	}
+
+	// Each body is bounded by MaxBlockBytes as on the serial path.
+	largestOnly := &ReadOptions{MaxBlockBytes: int(largest.Size)}
+	if _, err := DecodeAll(bytes.NewReader(data), largestOnly); err != nil {
+		t.Fatalf("serial limited DecodeAll: %v", err)
+	}
+	largestOnly.Concurrency = 4
+	if _, err := DecodeAll(bytes.NewReader(data), largestOnly); err != nil {
+		t.Fatalf("parallel limited DecodeAll: %v", err)
+	}
+	largestOnly.MaxBlockBytes--
+	if _, err := DecodeAll(bytes.NewReader(data), largestOnly); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("block limit error = %v, want ErrReadLimitExceeded", err)
+	}
+}
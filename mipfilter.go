@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// MipFilter selects the downsampling filter used to generate mipmaps.
+type MipFilter uint8
+
+const (
+	// MipFilterBox averages 2x2 texel blocks. It is the default and matches earlier releases.
+	MipFilterBox MipFilter = iota
+	// MipFilterTriangle is a tent filter; slightly softer than box with fewer aliasing artifacts.
+	MipFilterTriangle
+	// MipFilterKaiser is a Kaiser-windowed sinc (width 3, alpha 4); sharp with mild ringing.
+	MipFilterKaiser
+	// MipFilterLanczos is a 3-lobe Lanczos filter; the sharpest, with the most ringing.
+	MipFilterLanczos
+)
+
+// String returns the filter name.
+func (filter MipFilter) String() string {
+	switch filter {
+	case MipFilterBox:
+		return "Box"
+	case MipFilterTriangle:
+		return "Triangle"
+	case MipFilterKaiser:
+		return "Kaiser"
+	case MipFilterLanczos:
+		return "Lanczos"
+	default:
+		return fmt.Sprintf("MipFilter(%d)", filter)
+	}
+}
+
+// ParseMipFilter returns the filter with the given name, ignoring case.
+func ParseMipFilter(name string) (MipFilter, error) {
+	for filter := MipFilterBox; filter <= MipFilterLanczos; filter++ {
+		if strings.EqualFold(name, filter.String()) {
+			return filter, nil
+		}
+	}
+
+	return MipFilterBox, fmt.Errorf("%w: %q", ErrInvalidMipFilter, name)
+}
+
+// validateMipFilter reports whether filter is supported for writing.
+func validateMipFilter(filter MipFilter) error {
+	if filter > MipFilterLanczos {
+		return fmt.Errorf("%w: %d", ErrInvalidMipFilter, filter)
+	}
+
+	return nil
+}
+
+// support returns the filter radius in destination texels.
+func (filter MipFilter) support() float64 {
+	switch filter {
+	case MipFilterTriangle:
+		return 1
+	case MipFilterKaiser, MipFilterLanczos:
+		return 3
+	default:
+		return 0.5
+	}
+}
+
+// weight evaluates the filter kernel at t destination texels from the center.
+func (filter MipFilter) weight(t float64) float64 {
+	t = math.Abs(t)
+	switch filter {
+	case MipFilterTriangle:
+		return max(0, 1-t)
+	case MipFilterKaiser:
+		if t >= 3 {
+			return 0
+		}
+		const alpha = 4
+		ratio := t / 3
+		return sinc(t) * besselI0(alpha*math.Sqrt(1-ratio*ratio)) / besselI0(alpha)
+	case MipFilterLanczos:
+		if t >= 3 {
+			return 0
+		}
+		return sinc(t) * sinc(t/3)
+	default:
+		if t <= 0.5 {
+			return 1
+		}
+		return 0
+	}
+}
+
+// sinc returns the normalized sinc function.
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	x *= math.Pi
+
+	return math.Sin(x) / x
+}
+
+// besselI0 returns the zeroth-order modified Bessel function of the first kind.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; term > sum*1e-12; k++ {
+		half := x / (2 * float64(k))
+		term *= half * half
+		sum += term
+	}
+
+	return sum
+}
+
+// filterTaps lists the source texels and normalized weights of one destination texel.
+type filterTaps struct {
+	weights []float32
+	start   int
+}
+
+// filterAxis computes the taps that resample srcSize texels to dstSize.
+// Edge texels are clamped; an unchanged axis is passed through untouched.
+func filterAxis(filter MipFilter, srcSize, dstSize int) []filterTaps {
+	taps := make([]filterTaps, dstSize)
+	if srcSize == dstSize {
+		for i := range taps {
+			taps[i] = filterTaps{start: i, weights: []float32{1}}
+		}
+		return taps
+	}
+
+	scale := float64(srcSize) / float64(dstSize)
+	radius := filter.support() * scale
+	for i := range taps {
+		center := (float64(i) + 0.5) * scale
+		first := int(math.Floor(center - radius))
+		last := int(math.Ceil(center + radius))
+
+		// Clamped edge texels repeat, so fold their weights into one tap each.
+		lo, hi := max(first, 0), min(last, srcSize-1)
+		weights := make([]float64, hi-lo+1)
+		sum := 0.0
+		for s := first; s <= last; s++ {
+			w := filter.weight((float64(s) + 0.5 - center) / scale)
+			weights[min(max(s, lo), hi)-lo] += w
+			sum += w
+		}
+
+		tap := filterTaps{start: lo, weights: make([]float32, len(weights))}
+		for k, w := range weights {
+			tap.weights[k] = float32(w / sum)
+		}
+		taps[i] = tap
+	}
+
+	return taps
+}
+
+// resampleRGBAF32 resizes src to width x height with a separable filter.
+func resampleRGBAF32(src *RGBAF32, width, height int, filter MipFilter) *RGBAF32 {
+	srcW, srcH := src.Rect.Dx(), src.Rect.Dy()
+
+	horizontal := NewRGBAF32(image.Rect(0, 0, width, srcH))
+	xTaps := filterAxis(filter, srcW, width)
+	for y := range srcH {
+		row := src.Pix[y*src.Stride:]
+		out := horizontal.Pix[y*horizontal.Stride:]
+		for x, tap := range xTaps {
+			var sum [4]float32
+			for k, w := range tap.weights {
+				i := (tap.start + k) * 4
+				sum[0] += row[i] * w
+				sum[1] += row[i+1] * w
+				sum[2] += row[i+2] * w
+				sum[3] += row[i+3] * w
+			}
+			copy(out[x*4:x*4+4], sum[:])
+		}
+	}
+
+	dst := NewRGBAF32(image.Rect(0, 0, width, height))
+	yTaps := filterAxis(filter, srcH, height)
+	for y, tap := range yTaps {
+		out := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		for k, w := range tap.weights {
+			row := horizontal.Pix[(tap.start+k)*horizontal.Stride:]
+			for i := range out {
+				out[i] += row[i] * w
+			}
+		}
+	}
+
+	return dst
+}
+
+// filterRGBAF32Mipmaps builds mipMapCount levels from base with filter.
+// Each level is resampled from the previous one at full float precision.
+func filterRGBAF32Mipmaps(base *RGBAF32, mipMapCount int, filter MipFilter) []*RGBAF32 {
+	mips := make([]*RGBAF32, 1, mipMapCount)
+	mips[0] = base
+	for level := 1; level < mipMapCount; level++ {
+		width := mipDimension(base.Rect.Dx(), level)
+		height := mipDimension(base.Rect.Dy(), level)
+		mips = append(mips, resampleRGBAF32(mips[level-1], width, height, filter))
+	}
+
+	return mips
+}
+
+// filterNRGBAMipmaps builds mipMapCount levels from img with filter,
+// reusing the level buffers in dst when large enough. Level 0 is img itself.
+// With linear set, RGB is treated as sRGB and filtered in linear light.
+func filterNRGBAMipmaps(dst []*image.NRGBA, img image.Image, mipMapCount int, filter MipFilter, linear bool) []*image.NRGBA {
+	base := toNRGBA(img)
+	out := dst[:0]
+	if cap(out) < mipMapCount {
+		out = make([]*image.NRGBA, 0, mipMapCount)
+	}
+	out = append(out, base)
+
+	levels := filterRGBAF32Mipmaps(nrgbaToFloat(base, linear), mipMapCount, filter)
+	for level := 1; level < mipMapCount; level++ {
+		var reuse *image.NRGBA
+		if level < len(dst) {
+			reuse = dst[level]
+		}
+		out = append(out, floatToNRGBA(reuse, levels[level], linear))
+	}
+
+	return out
+}
+
+// nrgbaToFloat converts img to 0..1 floats, decoding sRGB RGB when linear is set.
+func nrgbaToFloat(img *image.NRGBA, linear bool) *RGBAF32 {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	out := NewRGBAF32(image.Rect(0, 0, width, height))
+	for y := range height {
+		src := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		dst := out.Pix[y*out.Stride:]
+		for i, v := range src {
+			if linear && i%4 != 3 {
+				dst[i] = srgbToLinearF[v]
+			} else {
+				dst[i] = float32(v) / 255
+			}
+		}
+	}
+
+	return out
+}
+
+// floatToNRGBA quantizes img to 8 bits into reuse when it is large enough,
+// encoding RGB back to sRGB when linear is set.
+func floatToNRGBA(reuse *image.NRGBA, img *RGBAF32, linear bool) *image.NRGBA {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	need := width * height * 4
+	if reuse != nil && cap(reuse.Pix) >= need {
+		reuse.Pix = reuse.Pix[:need]
+		reuse.Stride = width * 4
+		reuse.Rect = image.Rect(0, 0, width, height)
+	} else {
+		reuse = image.NewNRGBA(image.Rect(0, 0, width, height))
+	}
+
+	for i, v := range img.Pix[:need] {
+		if linear && i%4 != 3 {
+			v = linearToSRGB(v)
+		}
+		reuse.Pix[i] = unitToU8(v)
+	}
+
+	return reuse
+}
+
+// unitToU8 maps 0..1 to 0..255 with clamping.
+func unitToU8(v float32) uint8 {
+	if !(v > 0) {
+		return 0
+	}
+	if v >= 1 {
+		return 0xff
+	}
+
+	return uint8(v*0xff + 0.5)
+}
+
+// linearToSRGB encodes a linear 0..1 intensity with the sRGB transfer function.
+func linearToSRGB(v float32) float32 {
+	if !(v > 0.0031308) {
+		return v * 12.92
+	}
+
+	return float32(1.055*math.Pow(float64(v), 1/2.4) - 0.055)
+}
@@ -0,0 +1,171 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestParseMipFilter(t *testing.T) {
+	t.Parallel()
+
+	for filter := MipFilterBox; filter <= MipFilterLanczos; filter++ {
+		got, err := ParseMipFilter(filter.String())
+		if err != nil || got != filter {
+			t.Fatalf("ParseMipFilter(%q) = %v, %v", filter.String(), got, err)
+		}
+	}
+	if got, err := ParseMipFilter("lanczos"); err != nil || got != MipFilterLanczos {
+		t.Fatalf("ParseMipFilter(lanczos) = %v, %v", got, err)
+	}
+	if _, err := ParseMipFilter("bicubic"); !errors.Is(err, ErrInvalidMipFilter) {
+		t.Fatalf("unknown filter error = %v", err)
+	}
+}
+
+func TestMipFiltersKeepConstantImages(t *testing.T) {
+	t.Parallel()
+
+	want := color.NRGBA{R: 200, G: 100, B: 7, A: 128}
+	img := image.NewNRGBA(image.Rect(0, 0, 13, 5))
+	for y := range 5 {
+		for x := range 13 {
+			img.SetNRGBA(x, y, want)
+		}
+	}
+
+	for filter := MipFilterBox; filter <= MipFilterLanczos; filter++ {
+		for _, linear := range []bool{false, true} {
+			mips := generateMipmaps(nil, img, 4, &WriteOptions{MipFilter: filter, LinearMips: linear})
+			if len(mips) != 4 {
+				t.Fatalf("%v: %d levels, want 4", filter, len(mips))
+			}
+			for level, mip := range mips {
+				wantSize := image.Pt(mipDimension(13, level), mipDimension(5, level))
+				if mip.Rect.Size() != wantSize {
+					t.Fatalf("%v level %d size %v, want %v", filter, level, mip.Rect.Size(), wantSize)
+				}
+				for y := range wantSize.Y {
+					for x := range wantSize.X {
+						if got := mip.NRGBAAt(x, y); got != want {
+							t.Fatalf("%v linear=%t level %d (%d,%d) = %v", filter, linear, level, x, y, got)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMipFilterBoxMatchesDefault(t *testing.T) {
+	t.Parallel()
+
+	img := gradientNRGBA(16, 8)
+	got := generateMipmaps(nil, img, 5, &WriteOptions{})
+	want := bcn.GenerateMipmapsN(img, 0, false)
+	for level := range want {
+		if !bytes.Equal(got[level].Pix, want[level].Pix) {
+			t.Fatalf("level %d differs from bcn box filter", level)
+		}
+	}
+}
+
+func TestLinearMips(t *testing.T) {
+	t.Parallel()
+
+	// A black/white checkerboard averages to 50% linear light, which is sRGB 188.
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := range 8 {
+		for x := range 8 {
+			v := uint8(255 * ((x + y) % 2))
+			img.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+
+	for _, filter := range []MipFilter{MipFilterBox, MipFilterTriangle} {
+		gamma := generateMipmaps(nil, img, 2, &WriteOptions{MipFilter: filter})[1].NRGBAAt(1, 1)
+		linear := generateMipmaps(nil, img, 2, &WriteOptions{MipFilter: filter, LinearMips: true})[1].NRGBAAt(1, 1)
+		if gamma.R < 126 || gamma.R > 129 || linear.R < 186 || linear.R > 190 || linear.A != 255 {
+			t.Fatalf("%v: gamma %v, linear %v", filter, gamma, linear)
+		}
+	}
+}
+
+func TestMipFilterSharpness(t *testing.T) {
+	t.Parallel()
+
+	// Fine vertical stripes of period 3 keep more contrast with sharper filters.
+	img := image.NewNRGBA(image.Rect(0, 0, 48, 4))
+	for y := range 4 {
+		for x := range 48 {
+			v := uint8(0)
+			if x%6 < 3 {
+				v = 255
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+
+	contrast := func(filter MipFilter) int {
+		mip := generateMipmaps(nil, img, 2, &WriteOptions{MipFilter: filter})[1]
+		lo, hi := 255, 0
+		for x := 4; x < 20; x++ {
+			v := int(mip.NRGBAAt(x, 0).R)
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		return hi - lo
+	}
+	triangle, kaiser, lanczos := contrast(MipFilterTriangle), contrast(MipFilterKaiser), contrast(MipFilterLanczos)
+	if !(triangle < kaiser && kaiser <= lanczos) {
+		t.Fatalf("contrast triangle %d, kaiser %d, lanczos %d", triangle, kaiser, lanczos)
+	}
+}
+
+func TestEncodeWithMipFilter(t *testing.T) {
+	t.Parallel()
+
+	img := gradientNRGBA(32, 32)
+	var buf bytes.Buffer
+	opts := &WriteOptions{Format: bcn.FormatBGRA8, MipFilter: MipFilterKaiser, LinearMips: true}
+	if err := NewEncoder().EncodeWithOptions(&buf, img, opts); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	mip, err := DecodeMip(bytes.NewReader(buf.Bytes()), 1, nil)
+	if err != nil {
+		t.Fatalf("DecodeMip: %v", err)
+	}
+	want := generateMipmaps(nil, img, 2, opts)[1]
+	if !bytes.Equal(mip.(*image.NRGBA).Pix, want.Pix) {
+		t.Fatal("decoded level 1 differs from the filtered mip")
+	}
+
+	var hdr bytes.Buffer
+	if err := EncodeWithOptions(&hdr, img, &WriteOptions{Format: FormatRGBA16F, MipFilter: MipFilterLanczos}); err != nil {
+		t.Fatalf("HDR EncodeWithOptions: %v", err)
+	}
+
+	err = EncodeWithOptions(&bytes.Buffer{}, img, &WriteOptions{MipFilter: MipFilter(42)})
+	if !errors.Is(err, ErrInvalidMipFilter) {
+		t.Fatalf("invalid filter error = %v", err)
+	}
+}
+
+func gradientNRGBA(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(x * 255 / max(1, width-1)),  //nolint:gosec // bounded
+				G: uint8(y * 255 / max(1, height-1)), //nolint:gosec // bounded
+				B: uint8((x + y) * 7),                //nolint:gosec // wraps intentionally
+				A: 255,
+			})
+		}
+	}
+
+	return img
+}
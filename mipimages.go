@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+)
+
+// WriteMipImages writes an EDDS file from a caller-supplied mip chain.
+func WriteMipImages(path string, mips []image.Image, opts *WriteOptions) error {
+	enc := NewEncoder()
+	return writeFileAtomic(path, func(f *os.File) error {
+		return enc.EncodeMipImages(NewStreamWriter(f), mips, opts)
+	})
+}
+
+// EncodeMipImages writes an EDDS stream from a caller-supplied mip chain.
+func EncodeMipImages(w io.Writer, mips []image.Image, opts *WriteOptions) error {
+	return NewEncoder().EncodeMipImages(w, mips, opts)
+}
+
+// EncodeMipImages writes an EDDS stream from a caller-supplied mip chain,
+// ordered from largest to smallest, instead of generating mipmaps.
+// Level i must measure mipDimension of level 0 for i; the chain may stop
+// before 1x1 and is cut to opts.MaxMipMaps when that is positive.
+// SwizzleProfile and the format encoder apply to every level, while
+// the mip generation settings (MipFilter, LinearMips, NormalMap,
+// ToksvigPower, AlphaCoverageRef) are ignored.
+func (e *Encoder) EncodeMipImages(w io.Writer, mips []image.Image, opts *WriteOptions) error {
+	cfg := normalizeWriteOptions(opts)
+	if err := validateWriteOptions(&cfg); err != nil {
+		return err
+	}
+
+	width, height, err := validateMipImages(mips)
+	if err != nil {
+		return err
+	}
+	if cfg.MaxMipMaps > 0 && cfg.MaxMipMaps < len(mips) {
+		mips = mips[:cfg.MaxMipMaps]
+	}
+
+	payloads, err := e.encodeMipImages(mips, &cfg)
+	if err != nil {
+		return err
+	}
+
+	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
+	if err != nil {
+		return err
+	}
+
+	return e.writeFromBlocks(w, cfg.Format, cfg.ColorSpace, width, height, texture2D, payloads, compression, cfg.Concurrency)
+}
+
+// encodeMipImages encodes a validated mip chain level by level with cfg.
+// The returned payloads are Encoder-owned and valid until the next call.
+func (e *Encoder) encodeMipImages(mips []image.Image, cfg *WriteOptions) ([][]byte, error) {
+	if isHDRFormat(cfg.Format) {
+		levels := make([]*RGBAF32, len(mips))
+		for i, mip := range mips {
+			levels[i] = toRGBAF32(mip)
+		}
+		return encodeHDRImages(levels, cfg)
+	}
+
+	levels := make([]*image.NRGBA, len(mips))
+	for i, mip := range mips {
+		levels[i] = toNRGBA(mip)
+	}
+	return e.encodeImages(levels, cfg)
+}
+
+// validateMipImages checks that mips form a mip chain and returns the level 0 size.
+func validateMipImages(mips []image.Image) (width, height int, err error) {
+	if len(mips) == 0 {
+		return 0, 0, ErrEmptyMipmaps
+	}
+	if mips[0] == nil {
+		return 0, 0, fmt.Errorf("%w: level 0 is nil", ErrMipmapSizeMismatch)
+	}
+
+	bounds := mips[0].Bounds()
+	width, height = bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("%w: level 0 is %dx%d", ErrMipmapSizeMismatch, width, height)
+	}
+	full, err := calculateMipMapCount(width, height)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(mips) > full {
+		return 0, 0, fmt.Errorf("%w: %d levels, a %dx%d texture has at most %d", ErrMipmapSizeMismatch, len(mips), width, height, full)
+	}
+
+	for level, mip := range mips[1:] {
+		level++
+		wantW, wantH := mipDimension(width, level), mipDimension(height, level)
+		if mip == nil {
+			return 0, 0, fmt.Errorf("%w: level %d is nil", ErrMipmapSizeMismatch, level)
+		}
+		if size := mip.Bounds().Size(); size.X != wantW || size.Y != wantH {
+			return 0, 0, fmt.Errorf("%w: level %d is %dx%d, want %dx%d", ErrMipmapSizeMismatch, level, size.X, size.Y, wantW, wantH)
+		}
+	}
+
+	return width, height, nil
+}
@@ -0,0 +1,104 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+// paintedChain returns a 16x8 chain of solid levels, one color per level.
+func paintedChain() []image.Image {
+	colors := []color.NRGBA{
+		{R: 255, A: 255},
+		{G: 255, A: 255},
+		{B: 255, A: 255},
+		{R: 255, G: 255, A: 255},
+		{R: 40, G: 40, B: 40, A: 128},
+	}
+	mips := make([]image.Image, len(colors))
+	for level, c := range colors {
+		img := image.NewNRGBA(image.Rect(0, 0, mipDimension(16, level), mipDimension(8, level)))
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+		mips[level] = img
+	}
+
+	return mips
+}
+
+func TestEncodeMipImages(t *testing.T) {
+	t.Parallel()
+
+	mips := paintedChain()
+	var buf bytes.Buffer
+	if err := EncodeMipImages(&buf, mips, &WriteOptions{Format: bcn.FormatBGRA8}); err != nil {
+		t.Fatalf("EncodeMipImages: %v", err)
+	}
+	decoded, err := DecodeAll(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	if len(decoded) != len(mips) {
+		t.Fatalf("levels = %d, want %d", len(decoded), len(mips))
+	}
+	for level, img := range decoded {
+		if got, want := img.At(0, 0), mips[level].At(0, 0); got != want {
+			t.Fatalf("level %d = %v, want %v", level, got, want)
+		}
+	}
+
+	// Swizzle applies per level, and MaxMipMaps cuts the chain.
+	buf.Reset()
+	opts := &WriteOptions{Format: bcn.FormatBGRA8, MaxMipMaps: 2, SwizzleProfile: SwizzleProfileAlphaToRGB}
+	if err := NewEncoder().EncodeMipImages(&buf, mips, opts); err != nil {
+		t.Fatalf("EncodeMipImages swizzle: %v", err)
+	}
+	decoded, err = DecodeAll(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil || len(decoded) != 2 {
+		t.Fatalf("DecodeAll swizzle: %d levels, %v", len(decoded), err)
+	}
+	if got := decoded[1].At(0, 0); got != (color.NRGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Fatalf("swizzled level 1 = %v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "painted.edds")
+	if err := WriteMipImages(path, mips, &WriteOptions{Format: bcn.FormatDXT1}); err != nil {
+		t.Fatalf("WriteMipImages: %v", err)
+	}
+	if _, err := Read(path); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if err := EncodeMipImages(&bytes.Buffer{}, mips, &WriteOptions{Format: FormatRGBA16F}); err != nil {
+		t.Fatalf("EncodeMipImages HDR: %v", err)
+	}
+}
+
+func TestEncodeMipImagesValidation(t *testing.T) {
+	t.Parallel()
+
+	mips := paintedChain()
+	tooMany := append(append([]image.Image{}, mips...), image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	wrongSize := append([]image.Image{}, mips...)
+	wrongSize[2] = image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	withNil := append([]image.Image{}, mips...)
+	withNil[1] = nil
+
+	for name, chain := range map[string][]image.Image{
+		"too many":   tooMany,
+		"wrong size": wrongSize,
+		"nil level":  withNil,
+	} {
+		if err := EncodeMipImages(&bytes.Buffer{}, chain, nil); !errors.Is(err, ErrMipmapSizeMismatch) {
+			t.Fatalf("%s: error = %v", name, err)
+		}
+	}
+	if err := EncodeMipImages(&bytes.Buffer{}, nil, nil); !errors.Is(err, ErrEmptyMipmaps) {
+		t.Fatalf("empty chain error = %v", err)
+	}
+}
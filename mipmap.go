@@ -4,6 +4,12 @@
 
 package edds
 
+import (
+	"image"
+
+	"github.com/woozymasta/bcn"
+)
+
 // calculateMipMapCount calculates the number of mipmap levels for a given width and height.
 func calculateMipMapCount(width, height int) (int, error) {
 	count := 1
@@ -30,6 +36,23 @@ func calculateMipMapCount(width, height int) (int, error) {
 	return count, nil
 }
 
+// writeMipMapCount returns the number of mip levels to write,
+// capped by maxMipMaps when it is positive.
+func writeMipMapCount(width, height, maxMipMaps int) (int, error) {
+	mipMapCount, err := calculateMipMapCount(width, height)
+	if err != nil {
+		return 0, err
+	}
+	if maxMipMaps > 0 && maxMipMaps < mipMapCount {
+		mipMapCount = maxMipMaps
+	}
+	if mipMapCount < 1 {
+		mipMapCount = 1
+	}
+
+	return mipMapCount, nil
+}
+
 // mipDimension calculates the dimension of a mipmap level.
 func mipDimension(base, level int) int {
 	result := base >> level
@@ -39,3 +62,23 @@ func mipDimension(base, level int) int {
 
 	return result
 }
+
+// generateMipmaps builds mipMapCount levels from img with the filter, gamma,
+// normal map, and alpha coverage settings of cfg, reusing the level buffers in dst when possible.
+// mipMapCount must be at least 1. Level 0 is img itself and must not be modified.
+func generateMipmaps(dst []*image.NRGBA, img image.Image, mipMapCount int, cfg *WriteOptions) []*image.NRGBA {
+	var mips []*image.NRGBA
+	if cfg.MipFilter == MipFilterBox {
+		mips = bcn.GenerateMipmapsInto(dst, img, mipMapCount, cfg.LinearMips)
+	} else {
+		mips = filterNRGBAMipmaps(dst, img, mipMapCount, cfg.MipFilter, cfg.LinearMips)
+	}
+	if cfg.NormalMap != NormalMapNone {
+		renormalizeMipmaps(mips, cfg.NormalMap, cfg.MipFilter, cfg.ToksvigPower)
+	}
+	if cfg.AlphaCoverageRef != 0 {
+		preserveAlphaCoverage(mips, cfg.AlphaCoverageRef)
+	}
+
+	return mips
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// NormalMapMode selects how generated mipmaps treat a tangent-space normal map.
+// Modes describe the source image before SwizzleProfile is applied,
+// so NormalMapXY pairs with SwizzleProfileNormalMapGA and SwizzleProfileNormalMapNOHQ,
+// and NormalMapXYZ with SwizzleProfileNormalSpecularMapXYZS.
+type NormalMapMode uint8
+
+const (
+	// NormalMapNone filters every channel as color.
+	NormalMapNone NormalMapMode = iota
+	// NormalMapXY reads X and Y from R and G and reconstructs Z.
+	// Blue is filtered as color.
+	NormalMapXY
+	// NormalMapXYZ reads X, Y and Z from R, G and B.
+	NormalMapXYZ
+)
+
+// String returns the mode name.
+func (mode NormalMapMode) String() string {
+	switch mode {
+	case NormalMapNone:
+		return "None"
+	case NormalMapXY:
+		return "XY"
+	case NormalMapXYZ:
+		return "XYZ"
+	default:
+		return fmt.Sprintf("NormalMapMode(%d)", mode)
+	}
+}
+
+// ParseNormalMapMode returns the mode with the given name, ignoring case.
+func ParseNormalMapMode(name string) (NormalMapMode, error) {
+	for mode := NormalMapNone; mode <= NormalMapXYZ; mode++ {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+
+	return NormalMapNone, fmt.Errorf("%w: mode %q", ErrInvalidNormalMap, name)
+}
+
+// validateNormalMap reports whether the normal map settings of cfg are usable.
+func validateNormalMap(cfg *WriteOptions) error {
+	if cfg.NormalMap > NormalMapXYZ {
+		return fmt.Errorf("%w: mode %d", ErrInvalidNormalMap, cfg.NormalMap)
+	}
+	if !(cfg.ToksvigPower >= 0) || math.IsInf(float64(cfg.ToksvigPower), 1) {
+		return fmt.Errorf("%w: ToksvigPower %v", ErrInvalidNormalMap, cfg.ToksvigPower)
+	}
+	if cfg.ToksvigPower > 0 && cfg.NormalMap == NormalMapNone {
+		return fmt.Errorf("%w: ToksvigPower requires NormalMap", ErrInvalidNormalMap)
+	}
+	if cfg.ToksvigPower > 0 && !cfg.SwizzleProfile.keepsAlpha() {
+		// The adjusted gloss lives in alpha, which these profiles overwrite.
+		return fmt.Errorf("%w: ToksvigPower with swizzle profile %s", ErrInvalidNormalMap, cfg.SwizzleProfile)
+	}
+
+	return nil
+}
+
+// renormalizeMipmaps replaces the normal channels of mips[1:] with vectors
+// filtered from level 0 and renormalized. The averaged vectors are carried
+// unnormalized from level to level, so their shortening measures the normal
+// variance under each texel. With a positive toksvigPower, alpha is read as
+// specular power (255 = toksvigPower) and scaled by the Toksvig factor
+// |n| / (|n| + s(1-|n|)), so glossy highlights widen instead of aliasing at distance.
+func renormalizeMipmaps(mips []*image.NRGBA, mode NormalMapMode, filter MipFilter, toksvigPower float32) {
+	if len(mips) < 2 || mode == NormalMapNone {
+		return
+	}
+
+	base := mips[0]
+	width, height := base.Rect.Dx(), base.Rect.Dy()
+	vectors := NewRGBAF32(image.Rect(0, 0, width, height))
+	for y := range height {
+		src := base.Pix[y*base.Stride:]
+		dst := vectors.Pix[y*vectors.Stride:]
+		for x := range width {
+			i := x * 4
+			n := [3]float32{unpackNormal(src[i]), unpackNormal(src[i+1]), 1}
+			if mode == NormalMapXYZ {
+				n[2] = unpackNormal(src[i+2])
+			} else {
+				n[2] = float32(math.Sqrt(float64(max(0, 1-n[0]*n[0]-n[1]*n[1]))))
+			}
+			n = normalize3(n)
+			copy(dst[i:i+3], n[:])
+			dst[i+3] = float32(src[i+3]) / 255
+		}
+	}
+
+	levels := filterRGBAF32Mipmaps(vectors, len(mips), filter)
+	for level, mip := range mips[1:] {
+		packNormals(mip, levels[level+1], mode, toksvigPower)
+	}
+}
+
+// packNormals writes the renormalized vectors of v into the normal channels of mip.
+func packNormals(mip *image.NRGBA, v *RGBAF32, mode NormalMapMode, toksvigPower float32) {
+	width, height := mip.Rect.Dx(), mip.Rect.Dy()
+	for y := range height {
+		dst := mip.Pix[y*mip.Stride:]
+		src := v.Pix[y*v.Stride:]
+		for x := range width {
+			i := x * 4
+			n := [3]float32{src[i], src[i+1], src[i+2]}
+			length := float32(math.Sqrt(float64(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])))
+			n = normalize3(n)
+
+			dst[i] = packNormal(n[0])
+			dst[i+1] = packNormal(n[1])
+			if mode == NormalMapXYZ {
+				dst[i+2] = packNormal(n[2])
+			}
+			if toksvigPower > 0 {
+				length = min(length, 1)
+				power := float32(dst[i+3]) / 255 * toksvigPower
+				if factor := length / (length + power*(1-length)); factor < 1 {
+					dst[i+3] = unitToU8(float32(dst[i+3]) / 255 * factor)
+				}
+			}
+		}
+	}
+}
+
+// normalize3 returns n scaled to unit length, or +Z for a zero vector.
+func normalize3(n [3]float32) [3]float32 {
+	length := float32(math.Sqrt(float64(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])))
+	if !(length > 1e-6) {
+		return [3]float32{0, 0, 1}
+	}
+
+	return [3]float32{n[0] / length, n[1] / length, n[2] / length}
+}
+
+// unpackNormal maps an 8-bit channel to -1..1.
+func unpackNormal(v uint8) float32 {
+	return float32(v)/127.5 - 1
+}
+
+// packNormal maps -1..1 to an 8-bit channel.
+func packNormal(v float32) uint8 {
+	return unitToU8((v + 1) / 2)
+}
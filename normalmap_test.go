@@ -0,0 +1,153 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+// bumpyNormalMap returns a normal map whose normals alternate between
+// two tilted directions, so plain averaging shortens them toward +Z.
+func bumpyNormalMap(size int, xyz bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			nx := float32(0.6)
+			if x%2 == 1 {
+				nx = -0.6
+			}
+			c := color.NRGBA{R: packNormal(nx), G: packNormal(0.3), B: 200, A: 255}
+			if xyz {
+				c.B = packNormal(float32(math.Sqrt(1 - 0.36 - 0.09)))
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func normalLength(c color.NRGBA, mode NormalMapMode) float64 {
+	x, y := float64(unpackNormal(c.R)), float64(unpackNormal(c.G))
+	z := math.Sqrt(max(0, 1-x*x-y*y))
+	if mode == NormalMapXYZ {
+		z = float64(unpackNormal(c.B))
+	}
+
+	return math.Sqrt(x*x + y*y + z*z)
+}
+
+func TestParseNormalMapMode(t *testing.T) {
+	t.Parallel()
+
+	for mode := NormalMapNone; mode <= NormalMapXYZ; mode++ {
+		if got, err := ParseNormalMapMode(mode.String()); err != nil || got != mode {
+			t.Fatalf("ParseNormalMapMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if _, err := ParseNormalMapMode("tangent"); !errors.Is(err, ErrInvalidNormalMap) {
+		t.Fatalf("unknown mode error = %v", err)
+	}
+}
+
+func TestRenormalizeMipmaps(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []NormalMapMode{NormalMapXY, NormalMapXYZ} {
+		for filter := MipFilterBox; filter <= MipFilterLanczos; filter++ {
+			img := bumpyNormalMap(8, mode == NormalMapXYZ)
+			mips := generateMipmaps(nil, img, 3, &WriteOptions{NormalMap: mode, MipFilter: filter})
+			c := mips[1].NRGBAAt(1, 1)
+			if length := normalLength(c, mode); math.Abs(length-1) > 0.02 {
+				t.Fatalf("%v %v: level 1 normal %v has length %.3f", mode, filter, c, length)
+			}
+			// Windowed sincs leave a small residue of the alternating X.
+			if x := unpackNormal(c.R); math.Abs(float64(x)) > 0.1 {
+				t.Fatalf("%v %v: level 1 X = %.3f, want 0", mode, filter, x)
+			}
+			if mode == NormalMapXY && c.B != 200 {
+				t.Fatalf("%v %v: blue = %d, want it filtered as color", mode, filter, c.B)
+			}
+		}
+	}
+
+	// Without the mode, XYZ normals shorten when averaged.
+	plain := generateMipmaps(nil, bumpyNormalMap(8, true), 2, &WriteOptions{})[1].NRGBAAt(1, 1)
+	if length := normalLength(plain, NormalMapXYZ); length > 0.9 {
+		t.Fatalf("plain averaged normal length %.3f; test input too weak", length)
+	}
+}
+
+func TestToksvig(t *testing.T) {
+	t.Parallel()
+
+	img := bumpyNormalMap(8, false)
+	mips := generateMipmaps(nil, img, 3, &WriteOptions{NormalMap: NormalMapXY, ToksvigPower: 64})
+	if a := mips[0].NRGBAAt(0, 0).A; a != 255 {
+		t.Fatalf("level 0 alpha = %d, want 255", a)
+	}
+	// Averaged vector length is 0.8: factor = 0.8 / (0.8 + 64*0.2) ~ 0.059.
+	if a := mips[1].NRGBAAt(1, 1).A; a < 12 || a > 18 {
+		t.Fatalf("level 1 alpha = %d, want about 15", a)
+	}
+
+	flat := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := range flat.Pix {
+		flat.Pix[i] = []uint8{128, 128, 255, 200}[i%4]
+	}
+	mips = generateMipmaps(nil, flat, 2, &WriteOptions{NormalMap: NormalMapXYZ, ToksvigPower: 64})
+	if a := mips[1].NRGBAAt(0, 0).A; a != 200 {
+		t.Fatalf("flat normals changed gloss to %d", a)
+	}
+}
+
+func TestEncodeNormalMapWithSwizzle(t *testing.T) {
+	t.Parallel()
+
+	img := bumpyNormalMap(16, false)
+	var buf bytes.Buffer
+	opts := &WriteOptions{Format: bcn.FormatBGRA8, NormalMap: NormalMapXY, SwizzleProfile: SwizzleProfileNormalMapGA}
+	if err := EncodeWithOptions(&buf, img, opts); err != nil {
+		t.Fatalf("EncodeWithOptions: %v", err)
+	}
+	mip, err := DecodeMip(bytes.NewReader(buf.Bytes()), 1, nil)
+	if err != nil {
+		t.Fatalf("DecodeMip: %v", err)
+	}
+	// NormalMapGA stores X in alpha and Y in green.
+	c := mip.(*image.NRGBA).NRGBAAt(2, 2)
+	if length := normalLength(color.NRGBA{R: c.A, G: c.G}, NormalMapXY); math.Abs(length-1) > 0.02 {
+		t.Fatalf("swizzled level 1 texel %v", c)
+	}
+
+	// Alpha-preserving profiles keep the Toksvig-adjusted gloss.
+	buf.Reset()
+	opts = &WriteOptions{Format: bcn.FormatBGRA8, NormalMap: NormalMapXY, ToksvigPower: 64, SwizzleProfile: SwizzleProfileNormalSpecularMapXYZS}
+	if err := EncodeWithOptions(&buf, img, opts); err != nil {
+		t.Fatalf("EncodeWithOptions XYZS: %v", err)
+	}
+	mip, err = DecodeMip(bytes.NewReader(buf.Bytes()), 1, nil)
+	if err != nil {
+		t.Fatalf("DecodeMip: %v", err)
+	}
+	if a := mip.(*image.NRGBA).NRGBAAt(1, 1).A; a < 12 || a > 18 {
+		t.Fatalf("XYZS level 1 alpha = %d, want about 15", a)
+	}
+
+	for _, bad := range []*WriteOptions{
+		{NormalMap: NormalMapMode(9)},
+		{ToksvigPower: 8},
+		{NormalMap: NormalMapXY, ToksvigPower: float32(math.NaN())},
+		{NormalMap: NormalMapXY, ToksvigPower: 8, SwizzleProfile: SwizzleProfileNormalMapGA},
+		{NormalMap: NormalMapXY, ToksvigPower: 8, SwizzleProfile: SwizzleProfileNormalMapNOHQ},
+	} {
+		if err := EncodeWithOptions(&bytes.Buffer{}, img, bad); !errors.Is(err, ErrInvalidNormalMap) {
+			t.Fatalf("options %+v error = %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// forEachLevel calls fn for levels 0..n-1 on up to workers goroutines,
+// handing out levels largest first. worker is in 0..workers-1 and lets fn
+// use per-worker scratch buffers. The returned error is the one of the lowest
+// failing level, the same error the serial path (workers <= 1) stops at.
+func forEachLevel(n, workers int, fn func(worker, level int) error) error {
+	workers = min(workers, n)
+	if workers <= 1 {
+		for level := range n {
+			if err := fn(0, level); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, n)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Go(func() {
+			for {
+				level := int(next.Add(1) - 1)
+				if level >= n {
+					return
+				}
+				errs[level] = fn(worker, level)
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
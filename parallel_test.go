@@ -0,0 +1,121 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestForEachLevelReturnsLowestError(t *testing.T) {
+	t.Parallel()
+
+	errLow, errHigh := errors.New("low"), errors.New("high")
+	for _, workers := range []int{0, 1, 3, 16} {
+		seen := make([]bool, 8)
+		err := forEachLevel(len(seen), workers, func(worker, level int) error {
+			if worker < 0 || worker >= max(1, workers) {
+				t.Errorf("workers %d: worker index %d out of range", workers, worker)
+			}
+			seen[level] = true
+			switch level {
+			case 2:
+				return errLow
+			case 5:
+				return errHigh
+			}
+			return nil
+		})
+		if !errors.Is(err, errLow) {
+			t.Fatalf("workers %d: err = %v, want %v", workers, err, errLow)
+		}
+		if workers > 1 {
+			for level, ok := range seen {
+				if !ok {
+					t.Fatalf("workers %d: level %d not visited", workers, level)
+				}
+			}
+		}
+	}
+}
+
+func TestConcurrencyOutputIdentical(t *testing.T) {
+	t.Parallel()
+
+	img := gradientNRGBA(64, 32)
+	cases := []WriteOptions{
+		{Format: bcn.FormatBGRA8, Compression: CompressionOptions{Mode: CompressionLZ4}},
+		{Format: bcn.FormatBGRA8, Compression: CompressionOptions{Mode: CompressionLZ4HC}},
+		{Format: bcn.FormatDXT5, Compression: CompressionOptions{Mode: CompressionNone}},
+		{Format: bcn.FormatBC7, SwizzleProfile: SwizzleProfileAlphaToRGB},
+		{Format: FormatRGBA16F},
+		{Format: bcn.FormatBGRA8, MipFilter: MipFilterLanczos, AlphaCoverageRef: 128},
+	}
+	for _, opts := range cases {
+		var want bytes.Buffer
+		if err := EncodeWithOptions(&want, img, &opts); err != nil {
+			t.Fatalf("%s serial: %v", FormatName(opts.Format), err)
+		}
+
+		// One encoder across runs also checks that grown scratch buffers are reused correctly.
+		enc := NewEncoder()
+		for _, concurrency := range []int{2, 4, 64, 3} {
+			parallel := opts
+			parallel.Concurrency = concurrency
+			var got bytes.Buffer
+			if err := enc.EncodeWithOptions(&got, img, &parallel); err != nil {
+				t.Fatalf("%s concurrency %d: %v", FormatName(opts.Format), concurrency, err)
+			}
+			if !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Fatalf("%s concurrency %d: output differs from serial", FormatName(opts.Format), concurrency)
+			}
+		}
+	}
+}
+
+func TestConcurrencyWriteAndLayouts(t *testing.T) {
+	t.Parallel()
+
+	img := gradientNRGBA(32, 32)
+	dir := t.TempDir()
+	serial := &WriteOptions{Format: bcn.FormatDXT1, Compression: CompressionOptions{Mode: CompressionLZ4}}
+	parallel := *serial
+	parallel.Concurrency = 4
+
+	// WriteWithOptions runs the package-level write path rather than an Encoder.
+	for i, opts := range []*WriteOptions{serial, &parallel} {
+		if err := WriteWithOptions(img, filepath.Join(dir, []string{"a.edds", "b.edds"}[i]), opts); err != nil {
+			t.Fatalf("WriteWithOptions: %v", err)
+		}
+	}
+	a, err := os.ReadFile(filepath.Join(dir, "a.edds"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "b.edds"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Fatal("WriteWithOptions: parallel output differs from serial")
+	}
+
+	var cube CubeTexture
+	for face := range cube.Faces {
+		cube.Faces[face] = []image.Image{img}
+	}
+	var want, got bytes.Buffer
+	if err := EncodeCube(&want, &cube, serial); err != nil {
+		t.Fatalf("EncodeCube serial: %v", err)
+	}
+	if err := EncodeCube(&got, &cube, &parallel); err != nil {
+		t.Fatalf("EncodeCube parallel: %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatal("EncodeCube: parallel output differs from serial")
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// Payloads holds the decompressed mip chain of an EDDS texture in its stored pixel format.
+type Payloads struct {
+	// Mipmaps holds raw mip payloads ordered from largest (level 0) to smallest,
+	// the order accepted by EncodeFromBlocks.
+	Mipmaps [][]byte
+	// Format is the stored pixel format.
+	Format bcn.Format
+	// Width is the level 0 width in pixels.
+	Width int
+	// Height is the level 0 height in pixels.
+	Height int
+}
+
+// ReadPayloads reads every stored mip payload of an EDDS file without decoding pixels.
+func ReadPayloads(path string, opts *ReadOptions) (*Payloads, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := validateInputFileSize(f, limits); err != nil {
+		return nil, err
+	}
+
+	return NewDecoder().DecodePayloads(f, opts)
+}
+
+// DecodePayloads reads every stored mip payload of an EDDS stream without decoding pixels.
+func DecodePayloads(r io.Reader, opts *ReadOptions) (*Payloads, error) {
+	return NewDecoder().DecodePayloads(r, opts)
+}
+
+// DecodePayloads reads every stored mip payload of an EDDS stream without decoding pixels.
+// Blocks are decompressed, but BCn and uncompressed data are returned exactly as stored,
+// so the result can be passed to EncodeFromBlocks without a lossy re-encode.
+// Returned payloads own their buffers and stay valid after later calls on the same Decoder.
+func (d *Decoder) DecodePayloads(r io.Reader, opts *ReadOptions) (*Payloads, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var payloads Payloads
+	err = d.readMipChain(r, limits.forPayloads(), func(chain *mipChain) error {
+		if err := validateTextureType(chain.header, chain.dx10); err != nil {
+			return err
+		}
+		payloads = Payloads{
+			Mipmaps: make([][]byte, chain.mipMapCount),
+			Format:  chain.format,
+			Width:   int(chain.header.Width),
+			Height:  int(chain.header.Height),
+		}
+		return nil
+	}, func(level, _, _ int, payload []byte) error {
+		payloads.Mipmaps[level] = bytes.Clone(payload)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &payloads, nil
+}
@@ -0,0 +1,41 @@
+package edds
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestPayloadsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	payloads, err := ReadPayloads(filepath.Join("testdata", "corpus", "mip-grid-256-DXTCompression.edds"), nil)
+	if err != nil {
+		t.Fatalf("ReadPayloads: %v", err)
+	}
+	if payloads.Format != bcn.FormatDXT5 || payloads.Width != 256 || payloads.Height != 256 || len(payloads.Mipmaps) != 9 {
+		t.Fatalf("payloads = %v %dx%d %d mips", payloads.Format, payloads.Width, payloads.Height, len(payloads.Mipmaps))
+	}
+	for level, mip := range payloads.Mipmaps {
+		size := 256 >> level
+		if len(mip) != expectedDataLength(bcn.FormatDXT5, size, size) {
+			t.Fatalf("level %d payload %d bytes", level, len(mip))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeFromBlocks(&buf, payloads.Format, payloads.Width, payloads.Height, payloads.Mipmaps); err != nil {
+		t.Fatalf("EncodeFromBlocks: %v", err)
+	}
+	got, err := DecodePayloads(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodePayloads: %v", err)
+	}
+	for level := range payloads.Mipmaps {
+		if !bytes.Equal(got.Mipmaps[level], payloads.Mipmaps[level]) {
+			t.Fatalf("level %d payload changed after repack", level)
+		}
+	}
+}
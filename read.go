@@ -24,6 +24,7 @@ const (
 	defaultMaxReadInputBytes   = 2 << 30
 
 	dx10ResourceDimensionTexture2D = 3
+	dx10ResourceDimensionTexture3D = 4
 	dx10MiscTextureCube            = 0x4
 )
 
@@ -37,10 +38,22 @@ type ReadOptions struct {
 	MaxBlockBytes int
 	// MaxDecodedBytes limits one decoded mip payload. Zero uses the default.
 	MaxDecodedBytes int
-	// MaxImageBytes limits the decoded NRGBA image. Zero uses the default.
+	// MaxImageBytes limits the decoded images: 4 bytes per pixel for NRGBA,
+	// 16 for HDR RGBAF32, and 12 with Linearize, which keeps the NRGBA source
+	// alive while filling the NRGBA64 copy. Payload-only readers ignore it.
+	// Zero uses the default.
 	MaxImageBytes int
 	// MaxInputBytes limits buffered stream and legacy EDDS input. Zero uses the default.
 	MaxInputBytes int64
+	// Concurrency is the number of blocks decompressed in parallel by the
+	// multi-level readers (DecodeAll, DecodePayloads, cube, array and volume).
+	// 0 and 1 keep the serial path. At most Concurrency block bodies of up to
+	// MaxBlockBytes are held at once, and payloads stay within MaxDecodedBytes.
+	Concurrency int
+	// Linearize converts decoded RGB from sRGB to linear for textures
+	// declared as ColorSpaceSRGB. Linearized images are *image.NRGBA64, so dark
+	// tones keep their precision. Other textures and alpha are left unchanged.
+	Linearize bool
 }
 
 type readLimits struct {
@@ -49,6 +62,15 @@ type readLimits struct {
 	maxDecodedBytes int
 	maxImageBytes   int
 	maxInputBytes   int64
+	// workers is the block decompression concurrency; it is not a limit
+	// but travels with them to every multi-level reader.
+	workers int
+	// linearize counts the NRGBA64 copy made by ReadOptions.Linearize on top
+	// of the NRGBA source; forColorSpace narrows it to sRGB streams.
+	linearize bool
+	// raw marks readers that return payloads without decoding images,
+	// so maxImageBytes does not apply.
+	raw bool
 }
 
 // limitedReader stops a sequential decode after the configured input limit.
@@ -90,6 +112,8 @@ func normalizeReadLimits(opts *ReadOptions) (readLimits, error) {
 	if opts.MaxMipMaps > 0 {
 		limits.maxMipMaps = opts.MaxMipMaps
 	}
+	limits.workers = opts.Concurrency
+	limits.linearize = opts.Linearize
 	if opts.MaxBlockBytes < 0 || opts.MaxDecodedBytes < 0 || opts.MaxImageBytes < 0 || opts.MaxInputBytes < 0 {
 		return readLimits{}, fmt.Errorf("%w: limits must not be negative", ErrInvalidReadOptions)
 	}
@@ -116,29 +140,75 @@ func normalizeReadLimits(opts *ReadOptions) (readLimits, error) {
 	return limits, nil
 }
 
-// ReadConfig reads EDDS file configuration without decoding image data.
-func ReadConfig(path string) (image.Config, error) {
+// TextureConfig extends image.Config with the stored texture properties.
+type TextureConfig struct {
+	image.Config
+	// Format is the stored pixel format.
+	Format bcn.Format
+	// ColorSpace is the color space declared by the headers.
+	ColorSpace ColorSpace
+	// MipMapCount is the number of mip levels declared by the header (at least 1).
+	MipMapCount int
+}
+
+// ReadTextureConfig reads EDDS file configuration, format, and color space
+// without decoding image data.
+func ReadTextureConfig(path string) (TextureConfig, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return image.Config{}, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+		return TextureConfig{}, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
 	}
 	defer func() { _ = f.Close() }()
 
-	header, dx10, err := readEDDSHeaders(f)
+	return DecodeTextureConfig(f)
+}
+
+// DecodeTextureConfig reads EDDS stream configuration, format, and color space
+// without decoding image data. Only the DDS headers are consumed from r.
+func DecodeTextureConfig(r io.Reader) (TextureConfig, error) {
+	header, dx10, err := readEDDSHeaders(r)
 	if err != nil {
-		return image.Config{}, err
+		return TextureConfig{}, err
 	}
 	if err := validateTextureType(header, dx10); err != nil {
-		return image.Config{}, err
+		return TextureConfig{}, err
 	}
 
-	return image.Config{
-		Width:      int(header.Width),
-		Height:     int(header.Height),
-		ColorModel: color.RGBAModel,
+	mipMapCount := 1
+	if (header.Caps&bcn.DDSCapsMipmap) != 0 && header.MipMapCount > 0 {
+		mipMapCount = int(header.MipMapCount)
+	}
+
+	return TextureConfig{
+		Config: image.Config{
+			Width:      int(header.Width),
+			Height:     int(header.Height),
+			ColorModel: color.RGBAModel,
+		},
+		Format:      detectFormat(header, dx10),
+		ColorSpace:  detectColorSpace(dx10),
+		MipMapCount: mipMapCount,
 	}, nil
 }
 
+// ReadConfig reads EDDS file configuration without decoding image data.
+func ReadConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return DecodeConfig(f)
+}
+
+// DecodeConfig reads EDDS stream configuration without decoding image data.
+// Only the DDS headers are consumed from r.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	cfg, err := DecodeTextureConfig(r)
+	return cfg.Config, err
+}
+
 // Read reads and decodes an EDDS file into an image.
 func Read(path string) (image.Image, error) {
 	return ReadWithOptions(path, nil)
@@ -154,6 +224,12 @@ func DecodeWithOptions(r io.Reader, opts *ReadOptions) (image.Image, error) {
 	return NewDecoder().DecodeWithOptions(r, opts)
 }
 
+// DecodeMip reads and decodes one mip level of an EDDS stream.
+// Level 0 is the largest mip. Nil opts uses default limits.
+func DecodeMip(r io.Reader, level int, opts *ReadOptions) (image.Image, error) {
+	return NewDecoder().DecodeMip(r, level, opts)
+}
+
 // ReadWithOptions reads and decodes an EDDS file with the given options.
 // Nil opts uses default decoding (no DecodeOptions passed to bcn).
 func ReadWithOptions(path string, opts *ReadOptions) (image.Image, error) {
@@ -178,6 +254,7 @@ func ReadWithOptions(path string, opts *ReadOptions) (image.Image, error) {
 	if err := validateTextureType(header, dx10); err != nil {
 		return nil, err
 	}
+	limits = limits.forColorSpace(dx10)
 
 	format := detectFormat(header, dx10)
 
@@ -194,7 +271,7 @@ func ReadWithOptions(path string, opts *ReadOptions) (image.Image, error) {
 	var mipData []byte
 	var mipWidth, mipHeight int
 	if hasBlockTable {
-		mipData, mipWidth, mipHeight, err = readLargestMipFromBlocks(f, header, format, mipMapCount, limits)
+		mipData, mipWidth, mipHeight, err = readMipFromBlocks(f, header, format, mipMapCount, 0, limits)
 	} else {
 		mipData, mipWidth, mipHeight, err = readLegacySingleBlock(f, header, dx10, format, limits)
 		if err != nil {
@@ -209,10 +286,13 @@ func ReadWithOptions(path string, opts *ReadOptions) (image.Image, error) {
 	if opts != nil {
 		decOpts = opts.DecodeOptions
 	}
-	rgbaData, err := bcn.DecodeImageWithOptions(mipData, mipWidth, mipHeight, format, decOpts)
+	rgbaData, err := decodeImage(mipData, mipWidth, mipHeight, format, decOpts)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDecodeImage, err)
 	}
+	if linearizes(opts, dx10) {
+		return linearizeImage(rgbaData), nil
+	}
 
 	return rgbaData, nil
 }
@@ -224,9 +304,15 @@ func ReadWithOptions(path string, opts *ReadOptions) (image.Image, error) {
 type Decoder struct {
 	img          *image.NRGBA
 	blockTable   []blockHeader
-	blockData    []byte
 	raw          []byte
 	decompressor blockDecompressor
+	// blocks streams block bodies straight into raw without buffering the compressed copy.
+	blocks blockReader
+	// Parallel multi-level reads keep one block body buffer and one LZ4
+	// decompressor per worker, and every payload of the chain.
+	blockBodies   [][]byte
+	raws          [][]byte
+	decompressors []blockDecompressor
 }
 
 // NewDecoder returns a ready-to-use Decoder.
@@ -241,21 +327,30 @@ func (d *Decoder) Decode(r io.Reader) (image.Image, error) {
 
 // DecodeWithOptions reads and decodes an EDDS stream with the given options.
 func (d *Decoder) DecodeWithOptions(r io.Reader, opts *ReadOptions) (image.Image, error) {
+	return d.DecodeMip(r, 0, opts)
+}
+
+// DecodeMip reads and decodes one mip level of an EDDS stream.
+// Level 0 is the largest mip; other block bodies are skipped without decompression.
+func (d *Decoder) DecodeMip(r io.Reader, level int, opts *ReadOptions) (image.Image, error) {
 	limits, err := normalizeReadLimits(opts)
 	if err != nil {
 		return nil, err
 	}
+	if level < 0 {
+		return nil, fmt.Errorf("%w: level %d", ErrMipLevelOutOfRange, level)
+	}
 
 	if rs, ok := r.(io.ReadSeeker); ok {
-		return d.decodeReadSeeker(rs, opts, limits)
+		return d.decodeReadSeeker(rs, level, opts, limits)
 	}
 
 	stream := bufio.NewReader(&limitedReader{r: r, remaining: limits.maxInputBytes})
-	return d.decodeStream(stream, opts, limits)
+	return d.decodeStream(stream, level, opts, limits)
 }
 
 // decodeReadSeeker decodes an EDDS stream and supports seeking back for legacy input.
-func (d *Decoder) decodeReadSeeker(r io.ReadSeeker, opts *ReadOptions, limits readLimits) (image.Image, error) {
+func (d *Decoder) decodeReadSeeker(r io.ReadSeeker, level int, opts *ReadOptions, limits readLimits) (image.Image, error) {
 	header, dx10, err := readEDDSHeaders(r)
 	if err != nil {
 		return nil, err
@@ -263,6 +358,7 @@ func (d *Decoder) decodeReadSeeker(r io.ReadSeeker, opts *ReadOptions, limits re
 	if err := validateTextureType(header, dx10); err != nil {
 		return nil, err
 	}
+	limits = limits.forColorSpace(dx10)
 
 	format := detectFormat(header, dx10)
 
@@ -279,8 +375,14 @@ func (d *Decoder) decodeReadSeeker(r io.ReadSeeker, opts *ReadOptions, limits re
 	var mipData []byte
 	var mipWidth, mipHeight int
 	if hasBlockTable {
-		mipData, mipWidth, mipHeight, err = d.readLargestMipFromBlocks(r, header, format, mipMapCount, limits)
+		if err := validateMipLevel(level, mipMapCount); err != nil {
+			return nil, err
+		}
+		mipData, mipWidth, mipHeight, err = d.readMipFromBlocks(r, header, format, mipMapCount, level, limits)
 	} else {
+		if err := validateMipLevel(level, 1); err != nil {
+			return nil, err
+		}
 		mipData, mipWidth, mipHeight, err = d.readLegacySingleBlock(r, header, dx10, format, limits)
 		if err != nil {
 			return nil, err
@@ -290,11 +392,11 @@ func (d *Decoder) decodeReadSeeker(r io.ReadSeeker, opts *ReadOptions, limits re
 		return nil, err
 	}
 
-	return d.decodePayload(mipData, mipWidth, mipHeight, format, opts)
+	return d.decodePayload(mipData, mipWidth, mipHeight, format, linearizes(opts, dx10), opts)
 }
 
 // decodeStream decodes a non-seekable EDDS stream without buffering the whole input.
-func (d *Decoder) decodeStream(r *bufio.Reader, opts *ReadOptions, limits readLimits) (image.Image, error) {
+func (d *Decoder) decodeStream(r *bufio.Reader, level int, opts *ReadOptions, limits readLimits) (image.Image, error) {
 	header, dx10, err := readEDDSHeaders(r)
 	if err != nil {
 		return nil, err
@@ -302,6 +404,7 @@ func (d *Decoder) decodeStream(r *bufio.Reader, opts *ReadOptions, limits readLi
 	if err := validateTextureType(header, dx10); err != nil {
 		return nil, err
 	}
+	limits = limits.forColorSpace(dx10)
 
 	format := detectFormat(header, dx10)
 	mipMapCount, err := readMipMapCount(header, limits)
@@ -314,47 +417,70 @@ func (d *Decoder) decodeStream(r *bufio.Reader, opts *ReadOptions, limits readLi
 		return nil, fmt.Errorf("%w: %w", ErrReadBlockTable, err)
 	}
 	if !hasBlockTable {
+		if err := validateMipLevel(level, 1); err != nil {
+			return nil, err
+		}
 		mipData, mipWidth, mipHeight, err := d.readLegacySingleBlockFromReader(r, header, format, limits)
 		if err != nil {
 			return nil, err
 		}
-		return d.decodePayload(mipData, mipWidth, mipHeight, format, opts)
+		return d.decodePayload(mipData, mipWidth, mipHeight, format, linearizes(opts, dx10), opts)
+	}
+	if err := validateMipLevel(level, mipMapCount); err != nil {
+		return nil, err
 	}
 
-	mipData, mipWidth, mipHeight, err := d.readLargestMipFromReader(r, header, format, mipMapCount, limits)
+	mipData, mipWidth, mipHeight, err := d.readMipFromReader(r, header, format, mipMapCount, level, limits)
 	if err != nil {
 		return nil, err
 	}
 
-	return d.decodePayload(mipData, mipWidth, mipHeight, format, opts)
+	return d.decodePayload(mipData, mipWidth, mipHeight, format, linearizes(opts, dx10), opts)
 }
 
-// decodePayload converts the selected EDDS mip payload into an NRGBA image.
+// decodePayload converts the selected EDDS mip payload into an NRGBA image,
+// or into a newly allocated RGBAF32 image for HDR formats.
+// linearize converts sRGB texels to linear after decoding.
 func (d *Decoder) decodePayload(
 	mipData []byte,
 	mipWidth, mipHeight int,
 	format bcn.Format,
+	linearize bool,
 	opts *ReadOptions,
 ) (image.Image, error) {
 	decOpts := (*bcn.DecodeOptions)(nil)
 	if opts != nil {
 		decOpts = opts.DecodeOptions
 	}
+	if isHDRFormat(format) {
+		img, err := decodeHDR(mipData, mipWidth, mipHeight, format, decOpts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDecodeImage, err)
+		}
+		return img, nil
+	}
+	if err := bcnFormat(format); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecodeImage, err)
+	}
 	rgbaData, err := bcn.DecodeImageInto(d.img, mipData, mipWidth, mipHeight, format, decOpts)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDecodeImage, err)
 	}
 	d.img = rgbaData
+	if linearize {
+		return linearizeImage(rgbaData), nil
+	}
 
 	return rgbaData, nil
 }
 
-// readLargestMipFromBlocks reads the largest mipmap from the blocks.
-func readLargestMipFromBlocks(
+// readMipFromBlocks reads one mipmap level from the blocks.
+func readMipFromBlocks(
 	r io.ReadSeeker,
 	header *bcn.DDSHeader,
 	format bcn.Format,
 	mipMapCount uint32,
+	level int,
 	limits readLimits,
 ) ([]byte, int, int, error) {
 	if mipMapCount == 0 {
@@ -369,54 +495,44 @@ func readLargestMipFromBlocks(
 		return nil, 0, 0, err
 	}
 
-	// EDDS writes the block table and payloads from smallest to largest mip.
-	// The largest mip is therefore the last logical level and is selected here.
+	// EDDS writes the block table and payloads from smallest to largest mip,
+	// so mip level N is stored at table index mipMapCount-N-1.
 	for i := range mipMapCount {
-		mipLevel := mipMapCount - i - 1
-		if mipLevel != 0 {
+		mipLevel := int(mipMapCount - i - 1)
+		if mipLevel != level {
 			if _, err := r.Seek(int64(table[i].Size), io.SeekCurrent); err != nil {
 				return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrSkipBlockBody, i, err)
 			}
 			continue
 		}
 
-		mipW := mipDimension(int(header.Width), int(mipLevel))
-		mipH := mipDimension(int(header.Height), int(mipLevel))
+		mipW := mipDimension(int(header.Width), mipLevel)
+		mipH := mipDimension(int(header.Height), mipLevel)
 
 		expectedSize, err := expectedReadDataLength(format, mipW, mipH, limits)
 		if err != nil {
 			return nil, 0, 0, err
 		}
 
-		block, err := readBlockBody(r, table[i])
-		if err != nil {
-			return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrReadBlockBody, i, err)
-		}
-
-		decompressed, err := decompressBlock(block, expectedSize)
+		var blocks blockReader
+		decompressed, err := blocks.readPayload(nil, r, int(i), table[i], expectedSize)
 		if err != nil {
-			return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrDecompressBlock, i, err)
-		}
-		if len(decompressed) != expectedSize {
-			return nil, 0, 0, fmt.Errorf(
-				"%w: expected %d, got %d",
-				ErrLargestMipSizeMismatch,
-				expectedSize,
-				len(decompressed))
+			return nil, 0, 0, err
 		}
 
 		return decompressed, mipW, mipH, nil
 	}
 
-	return nil, 0, 0, fmt.Errorf("%w: mipmaps=%d", ErrPickLargestMip, mipMapCount)
+	return nil, 0, 0, fmt.Errorf("%w: level %d, mipmaps=%d", ErrPickMip, level, mipMapCount)
 }
 
-// readLargestMipFromBlocksInto reads the largest mipmap using Decoder-owned buffers.
-func (d *Decoder) readLargestMipFromBlocksInto(
+// readMipFromBlocks reads one mipmap level using Decoder-owned buffers.
+func (d *Decoder) readMipFromBlocks(
 	r io.ReadSeeker,
 	header *bcn.DDSHeader,
 	format bcn.Format,
 	mipMapCount uint32,
+	level int,
 	limits readLimits,
 ) ([]byte, int, int, error) {
 	if mipMapCount == 0 {
@@ -432,68 +548,45 @@ func (d *Decoder) readLargestMipFromBlocksInto(
 		return nil, 0, 0, err
 	}
 
-	// EDDS writes the block table and payloads from smallest to largest mip.
-	// The largest mip is therefore the last logical level and is selected here.
+	// EDDS writes the block table and payloads from smallest to largest mip,
+	// so mip level N is stored at table index mipMapCount-N-1.
 	for i := range mipMapCount {
-		mipLevel := mipMapCount - i - 1
-		if mipLevel != 0 {
+		mipLevel := int(mipMapCount - i - 1)
+		if mipLevel != level {
 			if _, err := r.Seek(int64(table[i].Size), io.SeekCurrent); err != nil {
 				return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrSkipBlockBody, i, err)
 			}
 			continue
 		}
 
-		mipW := mipDimension(int(header.Width), int(mipLevel))
-		mipH := mipDimension(int(header.Height), int(mipLevel))
+		mipW := mipDimension(int(header.Width), mipLevel)
+		mipH := mipDimension(int(header.Height), mipLevel)
 
 		expectedSize, err := expectedReadDataLength(format, mipW, mipH, limits)
 		if err != nil {
 			return nil, 0, 0, err
 		}
 
-		block, data, err := readBlockBodyInto(d.blockData, r, table[i])
+		decompressed, err := d.blocks.readPayload(d.raw, r, int(i), table[i], expectedSize)
 		if err != nil {
-			return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrReadBlockBody, i, err)
-		}
-		d.blockData = data
-
-		decompressed, err := d.decompressor.decompressBlock(d.raw, block, expectedSize)
-		if err != nil {
-			return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrDecompressBlock, i, err)
+			return nil, 0, 0, err
 		}
-
 		d.raw = decompressed
-		if len(decompressed) != expectedSize {
-			return nil, 0, 0, fmt.Errorf(
-				"%w: expected %d, got %d",
-				ErrLargestMipSizeMismatch,
-				expectedSize,
-				len(decompressed))
-		}
 
 		return decompressed, mipW, mipH, nil
 	}
 
-	return nil, 0, 0, fmt.Errorf("%w: mipmaps=%d", ErrPickLargestMip, mipMapCount)
-}
-
-// readLargestMipFromBlocks reads the largest mipmap through the reusable Decoder path.
-func (d *Decoder) readLargestMipFromBlocks(
-	r io.ReadSeeker,
-	header *bcn.DDSHeader,
-	format bcn.Format,
-	mipMapCount uint32,
-	limits readLimits,
-) ([]byte, int, int, error) {
-	return d.readLargestMipFromBlocksInto(r, header, format, mipMapCount, limits)
+	return nil, 0, 0, fmt.Errorf("%w: level %d, mipmaps=%d", ErrPickMip, level, mipMapCount)
 }
 
-// readLargestMipFromReader reads the largest mipmap from a sequential EDDS stream.
-func (d *Decoder) readLargestMipFromReader(
+// readMipFromReader reads one mipmap level from a sequential EDDS stream.
+// Block bodies after the selected level are left unread.
+func (d *Decoder) readMipFromReader(
 	r io.Reader,
 	header *bcn.DDSHeader,
 	format bcn.Format,
 	mipMapCount uint32,
+	level int,
 	limits readLimits,
 ) ([]byte, int, int, error) {
 	table, err := readBlockTable(r, mipMapCount)
@@ -505,36 +598,30 @@ func (d *Decoder) readLargestMipFromReader(
 	}
 
 	for i := range mipMapCount {
-		mipLevel := mipMapCount - i - 1
-		if mipLevel != 0 {
+		mipLevel := int(mipMapCount - i - 1)
+		if mipLevel != level {
 			if err := discardBlockBody(r, table[i].Size); err != nil {
 				return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %w", ErrSkipBlockBody, i, err)
 			}
 			continue
 		}
 
-		mipW := mipDimension(int(header.Width), int(mipLevel))
-		mipH := mipDimension(int(header.Height), int(mipLevel))
+		mipW := mipDimension(int(header.Width), mipLevel)
+		mipH := mipDimension(int(header.Height), mipLevel)
 		expectedSize, err := expectedReadDataLength(format, mipW, mipH, limits)
 		if err != nil {
 			return nil, 0, 0, err
 		}
 
-		block, data, err := readBlockBodyInto(d.blockData, r, table[i])
+		decompressed, err := d.blocks.readPayload(d.raw, r, int(i), table[i], expectedSize)
 		if err != nil {
-			return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrReadBlockBody, i, err)
-		}
-		d.blockData = data
-
-		decompressed, err := d.decompressor.decompressBlock(d.raw, block, expectedSize)
-		if err != nil {
-			return nil, 0, 0, fmt.Errorf("%w: mipmap %d: %v", ErrDecompressBlock, i, err)
+			return nil, 0, 0, err
 		}
 		d.raw = decompressed
 		return decompressed, mipW, mipH, nil
 	}
 
-	return nil, 0, 0, fmt.Errorf("%w: mipmaps=%d", ErrPickLargestMip, mipMapCount)
+	return nil, 0, 0, fmt.Errorf("%w: level %d, mipmaps=%d", ErrPickMip, level, mipMapCount)
 }
 
 // hasBlockTableMagic reports whether the next bytes begin a current EDDS block table.
@@ -704,20 +791,21 @@ func readEDDSHeaders(r io.Reader) (*bcn.DDSHeader, *bcn.DDSHeaderDX10, error) {
 
 // validateTextureType ensures the DDS resource maps to one NRGBA image.
 func validateTextureType(header *bcn.DDSHeader, dx10 *bcn.DDSHeaderDX10) error {
-	if (header.Caps2 & bcn.DDSCaps2Cubemap) != 0 {
-		return fmt.Errorf("%w: cubemaps are not supported", ErrUnsupportedTextureType)
-	}
-	if dx10 == nil {
-		return nil
-	}
-	if dx10.ResourceDimension != dx10ResourceDimensionTexture2D {
-		return fmt.Errorf("%w: DX10 resource dimension %d", ErrUnsupportedTextureType, dx10.ResourceDimension)
+	layout, err := readTextureLayout(header, dx10)
+	if err != nil {
+		return err
 	}
-	if dx10.ArraySize != 1 {
-		return fmt.Errorf("%w: DX10 array size %d", ErrUnsupportedTextureType, dx10.ArraySize)
+	if layout != texture2D {
+		return fmt.Errorf("%w: %s", ErrUnsupportedTextureType, layout.describe())
 	}
-	if (dx10.MiscFlag & dx10MiscTextureCube) != 0 {
-		return fmt.Errorf("%w: DX10 cubemaps are not supported", ErrUnsupportedTextureType)
+
+	return nil
+}
+
+// validateMipLevel ensures level addresses one of mipMapCount stored levels.
+func validateMipLevel(level int, mipMapCount uint32) error {
+	if level < 0 || uint64(level) >= uint64(mipMapCount) {
+		return fmt.Errorf("%w: level %d, mipmaps %d", ErrMipLevelOutOfRange, level, mipMapCount)
 	}
 
 	return nil
@@ -747,9 +835,40 @@ func validateBlockTable(table []blockHeader, limits readLimits) error {
 	return nil
 }
 
+// forColorSpace keeps linearize accounting only for streams declared sRGB,
+// matching the textures ReadOptions.Linearize converts.
+func (l readLimits) forColorSpace(dx10 *bcn.DDSHeaderDX10) readLimits {
+	l.linearize = l.linearize && detectColorSpace(dx10) == ColorSpaceSRGB
+	return l
+}
+
+// forPayloads drops the decoded image limit for readers that return payloads only.
+func (l readLimits) forPayloads() readLimits {
+	l.raw = true
+	return l
+}
+
+// imageLength returns the decoded image bytes held for one surface:
+// none for raw payload readers, and the NRGBA source plus its NRGBA64 copy
+// (12 bytes per pixel) when linearizing.
+func (l readLimits) imageLength(format bcn.Format, width, height int) (int, error) {
+	if l.raw {
+		return 0, nil
+	}
+	size, err := decodedImageLength(format, width, height)
+	if err != nil || !l.linearize || isHDRFormat(format) {
+		return size, err
+	}
+	if size > maxInt/3 {
+		return 0, ErrSizeOverflow
+	}
+
+	return size * 3, nil
+}
+
 // expectedReadDataLength validates the raw mip payload and decoded image sizes.
 func expectedReadDataLength(format bcn.Format, width, height int, limits readLimits) (int, error) {
-	imageSize, err := expectedDataLengthChecked(bcn.FormatRGBA8, width, height)
+	imageSize, err := limits.imageLength(format, width, height)
 	if err != nil {
 		return 0, err
 	}
@@ -760,7 +879,7 @@ func expectedReadDataLength(format bcn.Format, width, height int, limits readLim
 	size, err := expectedDataLengthChecked(format, width, height)
 	if err != nil {
 		if err == ErrInvalidFormat {
-			return 0, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+			return 0, fmt.Errorf("%w: %s", ErrUnknownFormat, FormatName(format))
 		}
 		return 0, err
 	}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// recompressed holds original headers and recompressed blocks ordered from largest to smallest mip.
+type recompressed struct {
+	header *bcn.DDSHeader
+	dx10   *bcn.DDSHeaderDX10
+	blocks []*Block
+}
+
+// RecompressOptions configures Recompress.
+type RecompressOptions struct {
+	// Compression selects the block compression of the output.
+	Compression CompressionOptions
+	// Read limits the EDDS input. Nil uses default limits. Pixels are not
+	// decoded, so MaxImageBytes and Linearize do not apply.
+	Read *ReadOptions
+}
+
+// Recompress rewrites the block compression of an EDDS stream.
+// Blocks are decompressed and compressed again with opts.Compression, or stored
+// as COPY for CompressionNone; DDS headers and mip payloads stay bit-identical.
+// A legacy single-block input gains a one-entry block table.
+// Nil opts uses default LZ4 compression and read limits.
+// A StreamWriter output gets every block as soon as it is recompressed,
+// see StreamWriter; other writers receive the stream once every block is ready.
+func Recompress(r io.Reader, w io.Writer, opts *RecompressOptions) error {
+	if sw, ok := w.(*StreamWriter); ok {
+		return recompressStream(r, sw.ws, opts)
+	}
+
+	out, err := recompressBlocks(r, opts)
+	if err != nil {
+		return err
+	}
+
+	return out.write(w)
+}
+
+// RecompressFile rewrites the block compression of the EDDS file at src into dst.
+// dst is replaced atomically and may be the same path as src.
+// Blocks stream from src to the temporary file one at a time.
+func RecompressFile(src, dst string, opts *RecompressOptions) error {
+	limits, err := recompressReadLimits(opts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrOpenFile, src, err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := validateInputFileSize(f, limits); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(dst, func(out *os.File) error {
+		err := Recompress(f, NewStreamWriter(out), opts)
+		// Close src before replacing dst so in-place recompression works on every platform.
+		_ = f.Close()
+		return err
+	})
+}
+
+// recompressReadLimits returns the payload-only read limits of opts.
+func recompressReadLimits(opts *RecompressOptions) (readLimits, error) {
+	var read *ReadOptions
+	if opts != nil {
+		read = opts.Read
+	}
+	limits, err := normalizeReadLimits(read)
+	if err != nil {
+		return readLimits{}, err
+	}
+
+	return limits.forPayloads(), nil
+}
+
+// recompressSettings validates opts and returns the output compression and input limits.
+func recompressSettings(opts *RecompressOptions) (normalizedCompressionOptions, readLimits, error) {
+	var options CompressionOptions
+	if opts != nil {
+		options = opts.Compression
+	}
+	compression, err := normalizeCompressionOptions(options, true)
+	if err != nil {
+		return normalizedCompressionOptions{}, readLimits{}, err
+	}
+	limits, err := recompressReadLimits(opts)
+	if err != nil {
+		return normalizedCompressionOptions{}, readLimits{}, err
+	}
+
+	return compression, limits, nil
+}
+
+// validateRecompressChain rejects legacy single-block input that declares mipmaps,
+// since only level 0 is stored.
+func validateRecompressChain(chain *mipChain, limits readLimits) error {
+	if !chain.legacy {
+		return nil
+	}
+	declared, err := readMipMapCount(chain.header, limits)
+	if err != nil {
+		return err
+	}
+	if declared != 1 {
+		return fmt.Errorf("%w: header declares %d mipmaps", ErrLegacyMipmaps, declared)
+	}
+
+	return nil
+}
+
+// recompressStream reads r level by level and writes every recompressed block
+// to ws as soon as it is read, then fills in the block table.
+func recompressStream(r io.Reader, ws io.WriteSeeker, opts *RecompressOptions) error {
+	compression, limits, err := recompressSettings(opts)
+	if err != nil {
+		return err
+	}
+	start, err := ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSeekOutput, err)
+	}
+
+	var stream *blockStream
+	var compressor blockCompressor
+	err = NewDecoder().readMipChain(r, limits, func(chain *mipChain) error {
+		if err := validateRecompressChain(chain, limits); err != nil {
+			return err
+		}
+
+		var err error
+		stream, err = startBlockStream(ws, start, chain.header, chain.dx10, int(chain.mipMapCount), compression, &compressor, nil)
+		return err
+	}, func(level, _, _ int, payload []byte) error {
+		// Blocks arrive in file order and are written before the Decoder reuses payload.
+		return stream.write(level, payload)
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.finish()
+}
+
+// recompressBlocks reads every mip payload from r and compresses it with opts.
+func recompressBlocks(r io.Reader, opts *RecompressOptions) (*recompressed, error) {
+	compression, limits, err := recompressSettings(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var out recompressed
+	err = NewDecoder().readMipChain(r, limits, func(chain *mipChain) error {
+		if err := validateRecompressChain(chain, limits); err != nil {
+			return err
+		}
+
+		out.header = chain.header
+		out.dx10 = chain.dx10
+		out.blocks = make([]*Block, chain.mipMapCount)
+		return nil
+	}, func(level, _, _ int, payload []byte) error {
+		// compressBlockWithOptions may return a COPY block that aliases payload,
+		// which the Decoder reuses for the next level.
+		block, err := compressBlockWithOptions(bytes.Clone(payload), compression)
+		if err != nil {
+			return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, level, err)
+		}
+		out.blocks[level] = block
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// write writes the original headers followed by the recompressed blocks.
+func (c *recompressed) write(w io.Writer) error {
+	if err := writeDDSHeaders(w, c.header, c.dx10); err != nil {
+		return err
+	}
+
+	return writeBlocks(w, c.blocks)
+}
@@ -0,0 +1,127 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecompressKeepsHeadersAndPayloads(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "corpus", "mip-grid-256-ColorHQCompression.edds"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	want, err := DecodePayloads(bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("DecodePayloads: %v", err)
+	}
+	info, err := Inspect(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+
+	for _, opts := range []CompressionOptions{
+		{Mode: CompressionNone},
+		{Mode: CompressionLZ4},
+		{Mode: CompressionLZ4HC, HCLevel: 9},
+	} {
+		var buf bytes.Buffer
+		if err := Recompress(bytes.NewReader(data), &buf, &RecompressOptions{Compression: opts}); err != nil {
+			t.Fatalf("Recompress(%v): %v", opts.Mode, err)
+		}
+		out := buf.Bytes()
+		if !bytes.Equal(out[:info.DataOffset-int64(8*len(info.Blocks))], data[:info.DataOffset-int64(8*len(info.Blocks))]) {
+			t.Fatalf("Recompress(%v) changed DDS headers", opts.Mode)
+		}
+
+		got, err := DecodePayloads(bytes.NewReader(out), nil)
+		if err != nil {
+			t.Fatalf("DecodePayloads(%v): %v", opts.Mode, err)
+		}
+		for level := range want.Mipmaps {
+			if !bytes.Equal(got.Mipmaps[level], want.Mipmaps[level]) {
+				t.Fatalf("Recompress(%v) level %d payload changed", opts.Mode, level)
+			}
+		}
+
+		if opts.Mode == CompressionNone {
+			gotInfo, err := Inspect(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			for _, block := range gotInfo.Blocks {
+				if block.Magic != BlockMagicCOPY {
+					t.Fatalf("CompressionNone block %d magic = %q", block.Index, block.Magic)
+				}
+			}
+		}
+	}
+}
+
+func TestRecompressFileInPlace(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "corpus", "mip-grid-256-DXTCompression.edds"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "texture.edds")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	opts := &RecompressOptions{Compression: CompressionOptions{Mode: CompressionLZ4HC, HCLevel: 9}}
+	var buffered bytes.Buffer
+	if err := Recompress(bytes.NewReader(data), &buffered, opts); err != nil {
+		t.Fatalf("Recompress: %v", err)
+	}
+	if err := RecompressFile(path, path, opts); err != nil {
+		t.Fatalf("RecompressFile: %v", err)
+	}
+
+	// The streamed file matches the buffered stream byte for byte.
+	streamed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(streamed, buffered.Bytes()) {
+		t.Fatalf("streamed output differs from buffered output")
+	}
+
+	want, err := DecodePayloads(bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("DecodePayloads: %v", err)
+	}
+	got, err := ReadPayloads(path, nil)
+	if err != nil {
+		t.Fatalf("ReadPayloads: %v", err)
+	}
+	for level := range want.Mipmaps {
+		if !bytes.Equal(got.Mipmaps[level], want.Mipmaps[level]) {
+			t.Fatalf("level %d payload changed", level)
+		}
+	}
+}
+
+func TestRecompressReadLimits(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "corpus", "mip-grid-256-ColorHQCompression.edds"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	// Payloads are recompressed without decoding, so only payload limits apply.
+	opts := &RecompressOptions{Read: &ReadOptions{MaxImageBytes: 16}}
+	if err := Recompress(bytes.NewReader(data), &bytes.Buffer{}, opts); err != nil {
+		t.Fatalf("Recompress with MaxImageBytes: %v", err)
+	}
+	opts.Read = &ReadOptions{MaxDecodedBytes: 1024}
+	if err := Recompress(bytes.NewReader(data), &bytes.Buffer{}, opts); !errors.Is(err, ErrReadLimitExceeded) {
+		t.Fatalf("Recompress error = %v, want ErrReadLimitExceeded", err)
+	}
+}
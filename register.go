@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"image"
+	"strings"
+
+	"github.com/woozymasta/bcn"
+)
+
+// formatName is the name registered with the standard image package.
+const formatName = "edds"
+
+// init registers EDDS with image.Decode and image.DecodeConfig.
+func init() {
+	for _, magic := range imageFormatMagics() {
+		image.RegisterFormat(formatName, magic, Decode, DecodeConfig)
+	}
+}
+
+// imageFormatMagics returns image.RegisterFormat patterns that identify EDDS input.
+// Plain DDS files share the "DDS " signature, so each pattern also requires
+// the ENF1 marker in Reserved1[1] or a COPY/LZ4 block-table entry after the headers.
+func imageFormatMagics() []string {
+	const (
+		ddsMagic         = "DDS "
+		enf1Offset       = 4 + 7*4 + 4 // Reserved1[1] at byte 36: after magic, 7 header fields and Reserved1[0]
+		blockTableOffset = 4 + bcn.DDSHeaderSize
+		dx10HeaderSize   = 20
+	)
+
+	wildcards := func(n int) string { return strings.Repeat("?", n) }
+
+	magics := []string{ddsMagic + wildcards(enf1Offset-len(ddsMagic)) + "ENF1"}
+	for _, offset := range []int{blockTableOffset, blockTableOffset + dx10HeaderSize} {
+		for _, blockMagic := range []string{BlockMagicCOPY, BlockMagicLZ4} {
+			magics = append(magics, ddsMagic+wildcards(offset-len(ddsMagic))+blockMagic)
+		}
+	}
+
+	return magics
+}
@@ -0,0 +1,63 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestImageDecodeRegistered(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range []string{
+		"mip-grid-256.edds",
+		"mip-grid-256-ColorHQCompression.edds",
+	} {
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(filepath.Join("testdata", "corpus", file))
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+
+			cfg, name, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("image.DecodeConfig: %v", err)
+			}
+			if name != formatName || cfg.Width != 256 || cfg.Height != 256 {
+				t.Fatalf("image.DecodeConfig = %q %dx%d, want edds 256x256", name, cfg.Width, cfg.Height)
+			}
+
+			img, name, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("image.Decode: %v", err)
+			}
+			if name != formatName || img.Bounds() != image.Rect(0, 0, 256, 256) {
+				t.Fatalf("image.Decode = %q %v, want edds 256x256", name, img.Bounds())
+			}
+		})
+	}
+}
+
+func TestImageDecodeIgnoresPlainDDS(t *testing.T) {
+	t.Parallel()
+
+	dds, err := bcn.EncodeDDS(image.NewNRGBA(image.Rect(0, 0, 4, 4)), bcn.FormatBGRA8)
+	if err != nil {
+		t.Fatalf("EncodeDDS: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := dds.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(buf.Bytes())); !errors.Is(err, image.ErrFormat) {
+		t.Fatalf("image.DecodeConfig error = %v, want image.ErrFormat", err)
+	}
+}
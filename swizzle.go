@@ -7,6 +7,7 @@ package edds
 import (
 	"fmt"
 	"image"
+	"strings"
 )
 
 // SwizzleProfile selects a Workbench-compatible channel transform before encoding.
@@ -65,6 +66,17 @@ func (profile SwizzleProfile) String() string {
 	}
 }
 
+// ParseSwizzleProfile returns the profile with the given Workbench name, ignoring case.
+func ParseSwizzleProfile(name string) (SwizzleProfile, error) {
+	for profile := SwizzleProfileNone; profile <= SwizzleProfileTerrainSuperTexture; profile++ {
+		if strings.EqualFold(name, profile.String()) {
+			return profile, nil
+		}
+	}
+
+	return SwizzleProfileNone, fmt.Errorf("%w: %q", ErrInvalidSwizzleProfile, name)
+}
+
 // validateSwizzleProfile reports whether profile is supported for writing.
 func validateSwizzleProfile(profile SwizzleProfile) error {
 	switch profile {
@@ -84,6 +96,16 @@ func validateSwizzleProfile(profile SwizzleProfile) error {
 	}
 }
 
+// keepsAlpha reports whether profile stores the source alpha in some channel.
+func (profile SwizzleProfile) keepsAlpha() bool {
+	switch profile {
+	case SwizzleProfileNormalMapGA, SwizzleProfileNormalMapNOHQ, SwizzleProfileSMDIToGS:
+		return false
+	default:
+		return true
+	}
+}
+
 // applySwizzleProfileInto applies profile to src using dst when it has matching bounds.
 func applySwizzleProfileInto(dst, src *image.NRGBA, profile SwizzleProfile) (*image.NRGBA, error) {
 	if err := validateSwizzleProfile(profile); err != nil {
@@ -0,0 +1,355 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/edds
+
+package edds
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"os"
+
+	"github.com/woozymasta/bcn"
+)
+
+// Texture3D holds a volume texture.
+// EDDS stores one block per mip level holding the depth slices of that level
+// from front to back; depth halves with every level like width and height.
+type Texture3D struct {
+	// Levels holds the depth slices of every mip level, Levels[level][z],
+	// ordered from largest (level 0) to smallest.
+	// Writers generate smaller levels from WriteOptions when only Levels[0] is set;
+	// otherwise every level must hold max(1, depth>>level) slices of the halved
+	// size, and the levels are written as given.
+	Levels [][]image.Image
+	// Format is the stored pixel format; it is set by readers and ignored by writers.
+	Format bcn.Format
+}
+
+// ReadVolume reads and decodes every depth slice and mip level of an EDDS volume texture file.
+func ReadVolume(path string, opts *ReadOptions) (*Texture3D, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrOpenFile, path, err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := validateInputFileSize(f, limits); err != nil {
+		return nil, err
+	}
+
+	return NewDecoder().DecodeVolume(f, opts)
+}
+
+// DecodeVolume reads and decodes every depth slice and mip level of an EDDS volume texture stream.
+func DecodeVolume(r io.Reader, opts *ReadOptions) (*Texture3D, error) {
+	return NewDecoder().DecodeVolume(r, opts)
+}
+
+// DecodeVolume reads and decodes every depth slice and mip level of an EDDS volume texture stream.
+// Returned images own their pixel buffers and stay valid after later calls on the same Decoder.
+func (d *Decoder) DecodeVolume(r io.Reader, opts *ReadOptions) (*Texture3D, error) {
+	limits, err := normalizeReadLimits(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	decOpts := (*bcn.DecodeOptions)(nil)
+	if opts != nil {
+		decOpts = opts.DecodeOptions
+	}
+
+	volume := &Texture3D{}
+	var layout textureLayout
+	var linearize bool
+	err = d.readMipChain(r, limits, func(chain *mipChain) error {
+		if !chain.layout.volume {
+			return fmt.Errorf("%w: %s is not a volume texture", ErrUnsupportedTextureType, chain.layout.describe())
+		}
+		layout = chain.layout
+		volume.Format = chain.format
+		linearize = linearizes(opts, chain.dx10)
+		volume.Levels = make([][]image.Image, chain.mipMapCount)
+		return nil
+	}, func(level, width, height int, payload []byte) error {
+		depth := layout.surfaces(level)
+		sliceSize := len(payload) / depth
+		volume.Levels[level] = make([]image.Image, depth)
+		for z := range depth {
+			img, err := decodeImage(payload[z*sliceSize:(z+1)*sliceSize], width, height, volume.Format, decOpts)
+			if err != nil {
+				return fmt.Errorf("%w: slice %d mipmap %d: %v", ErrDecodeImage, z, level, err)
+			}
+			if linearize {
+				img = linearizeImage(img)
+			}
+			volume.Levels[level][z] = img
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return volume, nil
+}
+
+// WriteVolume writes an EDDS volume texture file.
+func WriteVolume(volume *Texture3D, path string, opts *WriteOptions) error {
+	enc := NewEncoder()
+	return writeFileAtomic(path, func(f *os.File) error {
+		return enc.EncodeVolume(NewStreamWriter(f), volume, opts)
+	})
+}
+
+// EncodeVolume writes an EDDS volume texture stream.
+func EncodeVolume(w io.Writer, volume *Texture3D, opts *WriteOptions) error {
+	return NewEncoder().EncodeVolume(w, volume, opts)
+}
+
+// EncodeVolume writes an EDDS volume texture stream with a DX10 header.
+// Every slice of Levels[0] must have the same size. Supplied smaller levels are
+// written as given, see Texture3D.Levels. Otherwise they are generated
+// by averaging pairs of depth slices and halving each result in 2D;
+// HDR formats keep the slices as float RGBAF32 throughout.
+func (e *Encoder) EncodeVolume(w io.Writer, volume *Texture3D, opts *WriteOptions) error {
+	if volume == nil || len(volume.Levels) == 0 || len(volume.Levels[0]) == 0 {
+		return fmt.Errorf("%w: no slices", ErrInvalidVolumeTexture)
+	}
+	depth := len(volume.Levels[0])
+	if depth > maxTextureSlices {
+		return fmt.Errorf("%w: depth %d exceeds %d", ErrInvalidVolumeTexture, depth, maxTextureSlices)
+	}
+	width, height, err := sharedImageSize(volume.Levels[0], ErrInvalidVolumeTexture, "slice")
+	if err != nil {
+		return err
+	}
+
+	cfg := normalizeWriteOptions(opts)
+	if err := validateWriteOptions(&cfg); err != nil {
+		return err
+	}
+
+	supplied, err := volume.suppliedLevels(width, height)
+	if err != nil {
+		return err
+	}
+	mipMapCount, err := writeMipMapCount(max(width, depth), height, cfg.MaxMipMaps)
+	if err != nil {
+		return err
+	}
+	if supplied > 1 {
+		mipMapCount = supplied
+		if cfg.MaxMipMaps > 0 {
+			mipMapCount = min(mipMapCount, cfg.MaxMipMaps)
+		}
+	}
+
+	levels := make([][]byte, mipMapCount)
+	switch {
+	case supplied > 1:
+		err = e.encodeVolumeSupplied(levels, volume.Levels, &cfg)
+	case isHDRFormat(cfg.Format):
+		err = encodeVolumeHDR(levels, volume.Levels[0], width, height, &cfg)
+	default:
+		err = e.encodeVolumeLevels(levels, volume.Levels[0], width, height, &cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
+	if err != nil {
+		return err
+	}
+
+	layout := textureLayout{faces: 1, arraySize: 1, depth: depth, volume: true}
+	return e.writeFromBlocks(w, cfg.Format, cfg.ColorSpace, width, height, layout, levels, compression, cfg.Concurrency)
+}
+
+// suppliedLevels returns the number of levels in Levels. Every level after
+// the first must hold max(1, depth>>level) slices of the halved size,
+// and there may be no more levels than the full volume mip chain.
+func (v *Texture3D) suppliedLevels(width, height int) (int, error) {
+	count := len(v.Levels)
+	if count == 1 {
+		return 1, nil
+	}
+
+	depth := len(v.Levels[0])
+	full, err := calculateMipMapCount(max(width, depth), height)
+	if err != nil {
+		return 0, err
+	}
+	if count > full {
+		return 0, fmt.Errorf("%w: %d levels, a %dx%dx%d volume has at most %d", ErrInvalidVolumeTexture, count, width, height, depth, full)
+	}
+
+	for level, slices := range v.Levels[1:] {
+		level++
+		if want := mipDimension(depth, level); len(slices) != want {
+			return 0, fmt.Errorf("%w: level %d has %d slices, want %d", ErrInvalidVolumeTexture, level, len(slices), want)
+		}
+		wantW, wantH := mipDimension(width, level), mipDimension(height, level)
+		for z, img := range slices {
+			if img == nil {
+				return 0, fmt.Errorf("%w: level %d slice %d has no image", ErrInvalidVolumeTexture, level, z)
+			}
+			if size := img.Bounds().Size(); size.X != wantW || size.Y != wantH {
+				return 0, fmt.Errorf("%w: level %d slice %d is %dx%d, want %dx%d", ErrInvalidVolumeTexture, level, z, size.X, size.Y, wantW, wantH)
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// encodeVolumeSupplied fills levels with the encoded slices of the supplied levels.
+func (e *Encoder) encodeVolumeSupplied(levels [][]byte, supplied [][]image.Image, cfg *WriteOptions) error {
+	for level := range levels {
+		payloads, err := e.encodeMipImages(supplied[level], cfg)
+		if err != nil {
+			return fmt.Errorf("level %d: %w", level, err)
+		}
+		for _, payload := range payloads {
+			levels[level] = append(levels[level], payload...)
+		}
+	}
+
+	return nil
+}
+
+// encodeVolumeLevels fills levels with the encoded slices of every mip level.
+func (e *Encoder) encodeVolumeLevels(levels [][]byte, base []image.Image, width, height int, cfg *WriteOptions) error {
+	slices := make([]*image.NRGBA, len(base))
+	for z, img := range base {
+		slices[z] = toNRGBA(img)
+	}
+	for level := range levels {
+		if level > 0 {
+			slices = downscaleVolume(slices, mipDimension(width, level), mipDimension(height, level))
+		}
+
+		payloads, err := e.encodeImages(slices, cfg)
+		if err != nil {
+			return fmt.Errorf("level %d: %w", level, err)
+		}
+		for _, payload := range payloads {
+			levels[level] = append(levels[level], payload...)
+		}
+	}
+
+	return nil
+}
+
+// encodeVolumeHDR is encodeVolumeLevels for HDR formats.
+// Slices stay float through downsampling, so values above 1 survive.
+func encodeVolumeHDR(levels [][]byte, base []image.Image, width, height int, cfg *WriteOptions) error {
+	slices := make([]*RGBAF32, len(base))
+	for z, img := range base {
+		slices[z] = toRGBAF32(img)
+	}
+	for level := range levels {
+		if level > 0 {
+			slices = downscaleVolumeF32(slices, mipDimension(width, level), mipDimension(height, level))
+		}
+
+		payloads, err := encodeHDRImages(slices, cfg)
+		if err != nil {
+			return fmt.Errorf("level %d: %w", level, err)
+		}
+		for _, payload := range payloads {
+			levels[level] = append(levels[level], payload...)
+		}
+	}
+
+	return nil
+}
+
+// downscaleVolume builds the next volume mip level from slices:
+// pairs of depth slices are averaged, then halved to width x height.
+func downscaleVolume(slices []*image.NRGBA, width, height int) []*image.NRGBA {
+	next := make([]*image.NRGBA, max(1, len(slices)/2))
+	for z := range next {
+		merged := slices[2*z]
+		if 2*z+1 < len(slices) {
+			merged = averageNRGBA(slices[2*z], slices[2*z+1])
+		}
+
+		bounds := merged.Bounds()
+		if bounds.Dx() != width || bounds.Dy() != height {
+			merged = bcn.GenerateMipmapsN(merged, 2, false)[1]
+		}
+		next[z] = merged
+	}
+
+	return next
+}
+
+// averageNRGBA returns the per-channel rounded average of two same-sized images.
+func averageNRGBA(a, b *image.NRGBA) *image.NRGBA {
+	out := image.NewNRGBA(image.Rect(0, 0, a.Rect.Dx(), a.Rect.Dy()))
+	for y := range out.Rect.Dy() {
+		rowA := a.Pix[y*a.Stride:]
+		rowB := b.Pix[y*b.Stride:]
+		rowOut := out.Pix[y*out.Stride:]
+		for x := range out.Rect.Dx() * 4 {
+			rowOut[x] = uint8((uint16(rowA[x]) + uint16(rowB[x]) + 1) / 2)
+		}
+	}
+
+	return out
+}
+
+// downscaleVolumeF32 is downscaleVolume for float slices.
+func downscaleVolumeF32(slices []*RGBAF32, width, height int) []*RGBAF32 {
+	next := make([]*RGBAF32, max(1, len(slices)/2))
+	for z := range next {
+		merged := slices[2*z]
+		if 2*z+1 < len(slices) {
+			merged = averageRGBAF32(slices[2*z], slices[2*z+1])
+		}
+
+		bounds := merged.Bounds()
+		if bounds.Dx() != width || bounds.Dy() != height {
+			merged = generateRGBAF32Mipmaps(merged, 2, MipFilterBox)[1]
+		}
+		next[z] = merged
+	}
+
+	return next
+}
+
+// averageRGBAF32 returns the per-channel average of two same-sized float images.
+func averageRGBAF32(a, b *RGBAF32) *RGBAF32 {
+	width, height := a.Rect.Dx(), a.Rect.Dy()
+	out := NewRGBAF32(image.Rect(0, 0, width, height))
+	for y := range height {
+		rowA := a.Pix[y*a.Stride:]
+		rowB := b.Pix[y*b.Stride:]
+		rowOut := out.Pix[y*out.Stride:]
+		for x := range width * 4 {
+			rowOut[x] = (rowA[x] + rowB[x]) / 2
+		}
+	}
+
+	return out
+}
+
+// toNRGBA returns img as a zero-origin *image.NRGBA, converting when needed.
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+		return nrgba
+	}
+
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Rect, img, bounds.Min, draw.Src)
+	return out
+}
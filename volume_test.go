@@ -0,0 +1,141 @@
+package edds
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/woozymasta/bcn"
+)
+
+func TestVolumeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// 8x4 with 4 depth slices: black, white, red, red.
+	volume := &Texture3D{Levels: [][]image.Image{{
+		solidImage(8, color.NRGBA{A: 255}).SubImage(image.Rect(0, 0, 8, 4)),
+		solidImage(8, color.NRGBA{R: 255, G: 255, B: 255, A: 255}).SubImage(image.Rect(0, 0, 8, 4)),
+		solidImage(8, color.NRGBA{R: 255, A: 255}).SubImage(image.Rect(0, 0, 8, 4)),
+		solidImage(8, color.NRGBA{R: 255, A: 255}).SubImage(image.Rect(0, 0, 8, 4)),
+	}}}
+
+	var buf bytes.Buffer
+	if err := EncodeVolume(&buf, volume, &WriteOptions{Format: bcn.FormatRGBA8}); err != nil {
+		t.Fatalf("EncodeVolume: %v", err)
+	}
+
+	info, err := Inspect(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.DX10 == nil || info.DX10.ResourceDimension != dx10ResourceDimensionTexture3D || info.Header.Depth != 4 {
+		t.Fatalf("DX10 header = %+v, depth %d", info.DX10, info.Header.Depth)
+	}
+
+	got, err := DecodeVolume(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeVolume: %v", err)
+	}
+	wantDepths := []int{4, 2, 1, 1}
+	if len(got.Levels) != len(wantDepths) {
+		t.Fatalf("DecodeVolume returned %d levels, want %d", len(got.Levels), len(wantDepths))
+	}
+	for level, slices := range got.Levels {
+		if len(slices) != wantDepths[level] {
+			t.Fatalf("level %d depth = %d, want %d", level, len(slices), wantDepths[level])
+		}
+		if slices[0].Bounds() != image.Rect(0, 0, mipDimension(8, level), mipDimension(4, level)) {
+			t.Fatalf("level %d bounds = %v", level, slices[0].Bounds())
+		}
+	}
+
+	// Level 1 averages slices (black, white) and (red, red).
+	if c := color.NRGBAModel.Convert(got.Levels[1][0].At(0, 0)); c != (color.NRGBA{R: 128, G: 128, B: 128, A: 255}) {
+		t.Fatalf("level 1 slice 0 = %v, want mid gray", c)
+	}
+	if c := color.NRGBAModel.Convert(got.Levels[1][1].At(0, 0)); c != (color.NRGBA{R: 255, A: 255}) {
+		t.Fatalf("level 1 slice 1 = %v, want red", c)
+	}
+
+	if _, err := DecodeArray(bytes.NewReader(buf.Bytes()), nil); !errors.Is(err, ErrUnsupportedTextureType) {
+		t.Fatalf("DecodeArray volume error = %v, want ErrUnsupportedTextureType", err)
+	}
+}
+
+func TestVolumeHDRKeepsValuesAboveOne(t *testing.T) {
+	t.Parallel()
+
+	solid := func(v float32) *RGBAF32 {
+		img := NewRGBAF32(image.Rect(0, 0, 4, 4))
+		for y := range 4 {
+			for x := range 4 {
+				img.SetFloat(x, y, [4]float32{v, v / 4, 0.5, 1})
+			}
+		}
+		return img
+	}
+	volume := &Texture3D{Levels: [][]image.Image{{solid(3), solid(5)}}}
+
+	var buf bytes.Buffer
+	if err := EncodeVolume(&buf, volume, &WriteOptions{Format: FormatRGBA16F}); err != nil {
+		t.Fatalf("EncodeVolume: %v", err)
+	}
+	got, err := DecodeVolume(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeVolume: %v", err)
+	}
+
+	if c := got.Levels[0][1].(*RGBAF32).FloatAt(0, 0); c != [4]float32{5, 1.25, 0.5, 1} {
+		t.Fatalf("level 0 slice 1 = %v, want unclamped", c)
+	}
+	// Level 1 averages the two slices in float.
+	if c := got.Levels[1][0].(*RGBAF32).FloatAt(1, 1); c != [4]float32{4, 1, 0.5, 1} {
+		t.Fatalf("level 1 = %v, want {4 1 0.5 1}", c)
+	}
+}
+
+func TestEncodeVolumeSuppliedLevels(t *testing.T) {
+	t.Parallel()
+
+	// An averaged level 1 of red and white slices would never be pure blue.
+	red := color.NRGBA{R: 255, A: 255}
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	volume := &Texture3D{Levels: [][]image.Image{
+		{solidImage(4, red), solidImage(4, white)},
+		{solidImage(2, blue)},
+	}}
+
+	var buf bytes.Buffer
+	if err := EncodeVolume(&buf, volume, &WriteOptions{Format: bcn.FormatRGBA8}); err != nil {
+		t.Fatalf("EncodeVolume: %v", err)
+	}
+	got, err := DecodeVolume(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("DecodeVolume: %v", err)
+	}
+	if len(got.Levels) != 2 {
+		t.Fatalf("DecodeVolume returned %d levels, want 2", len(got.Levels))
+	}
+	if c := color.NRGBAModel.Convert(got.Levels[1][0].At(0, 0)); c != blue {
+		t.Fatalf("level 1 = %v, want supplied blue", c)
+	}
+
+	// A decoded volume round-trips its levels.
+	buf.Reset()
+	if err := EncodeVolume(&buf, got, &WriteOptions{Format: bcn.FormatRGBA8}); err != nil {
+		t.Fatalf("EncodeVolume decoded: %v", err)
+	}
+
+	// Levels must hold the halved depth and size.
+	volume.Levels[1] = []image.Image{solidImage(2, blue), solidImage(2, blue)}
+	if err := EncodeVolume(&bytes.Buffer{}, volume, nil); !errors.Is(err, ErrInvalidVolumeTexture) {
+		t.Fatalf("slice count error = %v, want ErrInvalidVolumeTexture", err)
+	}
+	volume.Levels[1] = []image.Image{solidImage(4, blue)}
+	if err := EncodeVolume(&bytes.Buffer{}, volume, nil); !errors.Is(err, ErrInvalidVolumeTexture) {
+		t.Fatalf("slice size error = %v, want ErrInvalidVolumeTexture", err)
+	}
+}
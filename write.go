@@ -25,9 +25,45 @@ type WriteOptions struct {
 	Format bcn.Format
 	// MaxMipMaps limits written mipmaps (0 = full chain).
 	MaxMipMaps int
+	// ColorSpace declares the color encoding of the written texels.
+	// ColorSpaceSRGB selects the *_UNORM_SRGB DXGI variant and requires DXT1/3/5,
+	// BC7, RGBA8, BGRA8 or BGRX8; the source pixels are stored as given,
+	// so they should already be sRGB-encoded. Any explicit color space writes a DX10 header;
+	// the zero value keeps the legacy header Workbench writes.
+	ColorSpace ColorSpace
+	// MipFilter selects the downsampling filter for generated mipmaps. Zero is a 2x2 box.
+	// Volume textures always use the box filter.
+	MipFilter MipFilter
+	// NormalMap marks the source as a tangent-space normal map: generated mip levels
+	// are filtered as vectors and renormalized instead of averaged as color.
+	// It applies before SwizzleProfile and is ignored for HDR formats and volume textures.
+	NormalMap NormalMapMode
+	// ToksvigPower enables Toksvig gloss adjustment for normal maps when positive:
+	// alpha is read as specular power with 255 meaning ToksvigPower, and lowered
+	// where averaged normals diverge. It requires NormalMap and a SwizzleProfile
+	// that keeps the source alpha, such as NormalSpecularMapXYZS or
+	// TerrainNormalSpecular_SYxX; profiles that overwrite alpha are rejected.
+	ToksvigPower float32
+	// AlphaCoverageRef is the alpha-test reference for cutout textures such as
+	// foliage and fences. When non-zero, the alpha of every generated mip level
+	// is rescaled so the fraction of texels with alpha above the reference
+	// matches level 0. It applies before SwizzleProfile and encoding,
+	// and is ignored for HDR formats and volume textures.
+	AlphaCoverageRef uint8
 	// SwizzleProfile transforms channels before encoding. Zero leaves channels unchanged.
 	// The profile is not stored in EDDS metadata and is not applied while reading.
 	SwizzleProfile SwizzleProfile
+	// LinearMips filters RGB in linear light, treating source pixels as sRGB-encoded,
+	// so mips of bright and dark detail keep their brightness at distance.
+	// Leave it off for data textures such as normal and mask maps. HDR formats
+	// are always filtered as given.
+	LinearMips bool
+	// Concurrency is the number of mip levels encoded and compressed in parallel.
+	// 0 and 1 keep the serial path; the output bytes are identical for every value.
+	// BCn encoders parallelize within a level as well, see bcn.EncodeOptions.Workers.
+	// Values above 1 hold the whole compressed chain before writing,
+	// even for a StreamWriter output.
+	Concurrency int
 	// Compress controls EDDS block compression (LZ4 if true, COPY if false).
 	//
 	// Deprecated: use Compression.Mode.
@@ -95,7 +131,7 @@ func EncodeFromBlocks(w io.Writer, format bcn.Format, width, height int, mipmaps
 		return err
 	}
 
-	return NewEncoder().writeFromBlocks(w, format, width, height, mipmaps, compression)
+	return NewEncoder().writeFromBlocks(w, format, ColorSpaceUnspecified, width, height, texture2D, mipmaps, compression, 1)
 }
 
 // EncodeFromBlocksWithCompression writes an EDDS stream from pre-encoded mip payloads.
@@ -115,7 +151,7 @@ func EncodeFromBlocksWithCompression(
 		return err
 	}
 
-	return NewEncoder().writeFromBlocks(w, format, width, height, mipmaps, compression)
+	return NewEncoder().writeFromBlocks(w, format, ColorSpaceUnspecified, width, height, texture2D, mipmaps, compression, 1)
 }
 
 // EncodeFromBlocksWithCompressionOptions writes an EDDS stream
@@ -133,7 +169,7 @@ func EncodeFromBlocksWithCompressionOptions(
 		return err
 	}
 
-	return NewEncoder().writeFromBlocks(w, format, width, height, mipmaps, compression)
+	return NewEncoder().writeFromBlocks(w, format, ColorSpaceUnspecified, width, height, texture2D, mipmaps, compression, 1)
 }
 
 // Encoder encodes EDDS streams while reusing internal buffers across calls.
@@ -144,7 +180,8 @@ type Encoder struct {
 	payloads      [][]byte
 	blockPayloads [][]byte
 	blocks        []*Block
-	compressor    blockCompressor
+	// compressors holds LZ4 scratch buffers, one per concurrent worker.
+	compressors []blockCompressor
 }
 
 // NewEncoder returns a ready-to-use Encoder.
@@ -172,7 +209,7 @@ func (e *Encoder) EncodeFromBlocks(w io.Writer, format bcn.Format, width, height
 		return err
 	}
 
-	return e.writeFromBlocks(w, format, width, height, mipmaps, compression)
+	return e.writeFromBlocks(w, format, ColorSpaceUnspecified, width, height, texture2D, mipmaps, compression, 1)
 }
 
 // EncodeFromBlocksWithCompression writes an EDDS stream from pre-encoded mip payloads.
@@ -192,7 +229,7 @@ func (e *Encoder) EncodeFromBlocksWithCompression(
 		return err
 	}
 
-	return e.writeFromBlocks(w, format, width, height, mipmaps, compression)
+	return e.writeFromBlocks(w, format, ColorSpaceUnspecified, width, height, texture2D, mipmaps, compression, 1)
 }
 
 // EncodeFromBlocksWithCompressionOptions writes an EDDS stream
@@ -210,7 +247,7 @@ func (e *Encoder) EncodeFromBlocksWithCompressionOptions(
 		return err
 	}
 
-	return e.writeFromBlocks(w, format, width, height, mipmaps, compression)
+	return e.writeFromBlocks(w, format, ColorSpaceUnspecified, width, height, texture2D, mipmaps, compression, 1)
 }
 
 // normalizeWriteOptions normalizes the write options.
@@ -229,7 +266,14 @@ func normalizeWriteOptions(opts *WriteOptions) WriteOptions {
 		cfg.Format = opts.Format
 	}
 	cfg.MaxMipMaps = opts.MaxMipMaps
+	cfg.ColorSpace = opts.ColorSpace
 	cfg.SwizzleProfile = opts.SwizzleProfile
+	cfg.MipFilter = opts.MipFilter
+	cfg.LinearMips = opts.LinearMips
+	cfg.AlphaCoverageRef = opts.AlphaCoverageRef
+	cfg.NormalMap = opts.NormalMap
+	cfg.ToksvigPower = opts.ToksvigPower
+	cfg.Concurrency = opts.Concurrency
 	cfg.Compress = opts.Compress
 	cfg.Compression = opts.Compression
 	cfg.EncodeOptions = opts.EncodeOptions
@@ -237,6 +281,19 @@ func normalizeWriteOptions(opts *WriteOptions) WriteOptions {
 	return cfg
 }
 
+// validateWriteOptions checks the enumerated settings of a normalized cfg.
+func validateWriteOptions(cfg *WriteOptions) error {
+	if err := validateSwizzleProfile(cfg.SwizzleProfile); err != nil {
+		return err
+	}
+
+	if err := validateMipFilter(cfg.MipFilter); err != nil {
+		return err
+	}
+
+	return validateNormalMap(cfg)
+}
+
 // WriteFromBlocks writes an EDDS file from pre-encoded mip payloads.
 // The mipmaps slice must be ordered from largest to smallest.
 func WriteFromBlocks(path string, format bcn.Format, width, height int, mipmaps [][]byte) error {
@@ -247,7 +304,7 @@ func WriteFromBlocks(path string, format bcn.Format, width, height int, mipmaps
 		return err
 	}
 
-	return writeFromBlocks(path, format, width, height, mipmaps, compression)
+	return writeFromBlocks(path, format, ColorSpaceUnspecified, width, height, mipmaps, compression, 1)
 }
 
 // WriteFromBlocksWithCompression writes an EDDS file from pre-encoded mip payloads.
@@ -267,7 +324,7 @@ func WriteFromBlocksWithCompression(
 		return err
 	}
 
-	return writeFromBlocks(path, format, width, height, mipmaps, compression)
+	return writeFromBlocks(path, format, ColorSpaceUnspecified, width, height, mipmaps, compression, 1)
 }
 
 // WriteFromBlocksWithCompressionOptions writes an EDDS file from pre-encoded mip payloads.
@@ -284,7 +341,7 @@ func WriteFromBlocksWithCompressionOptions(
 		return err
 	}
 
-	return writeFromBlocks(path, format, width, height, mipmaps, compression)
+	return writeFromBlocks(path, format, ColorSpaceUnspecified, width, height, mipmaps, compression, 1)
 }
 
 // writeWithOptions writes an EDDS file with full low-level options.
@@ -294,7 +351,7 @@ func writeWithOptions(
 	opts *WriteOptions,
 ) error {
 	cfg := normalizeWriteOptions(opts)
-	if err := validateSwizzleProfile(cfg.SwizzleProfile); err != nil {
+	if err := validateWriteOptions(&cfg); err != nil {
 		return err
 	}
 
@@ -302,35 +359,44 @@ func writeWithOptions(
 	width := bounds.Dx()
 	height := bounds.Dy()
 
-	mipMapCount, err := calculateMipMapCount(width, height)
+	mipMapCount, err := writeMipMapCount(width, height, cfg.MaxMipMaps)
 	if err != nil {
 		return err
 	}
-	if cfg.MaxMipMaps > 0 && cfg.MaxMipMaps < mipMapCount {
-		mipMapCount = cfg.MaxMipMaps
-	}
-	if mipMapCount < 1 {
-		mipMapCount = 1
-	}
 
-	mips := bcn.GenerateMipmapsN(img, mipMapCount, false)
-	if cfg.SwizzleProfile != SwizzleProfileNone {
-		for i, mip := range mips {
-			swizzled, err := applySwizzleProfileInto(nil, mip, cfg.SwizzleProfile)
-			if err != nil {
-				return err
+	var payloads [][]byte
+	if isHDRFormat(cfg.Format) {
+		payloads, err = encodeHDRImages(generateRGBAF32Mipmaps(toRGBAF32(img), mipMapCount, cfg.MipFilter), &cfg)
+		if err != nil {
+			return err
+		}
+	} else {
+		mips := generateMipmaps(nil, img, mipMapCount, &cfg)
+		if cfg.SwizzleProfile != SwizzleProfileNone {
+			for i, mip := range mips {
+				swizzled, err := applySwizzleProfileInto(nil, mip, cfg.SwizzleProfile)
+				if err != nil {
+					return err
+				}
+				mips[i] = swizzled
 			}
-			mips[i] = swizzled
 		}
-	}
 
-	payloads := make([][]byte, len(mips))
-	for i, mip := range mips {
-		data, _, _, err := bcn.EncodeImageWithOptions(mip, cfg.Format, cfg.EncodeOptions)
+		if err := bcnFormat(cfg.Format); err != nil {
+			return err
+		}
+		payloads = make([][]byte, len(mips))
+		err := forEachLevel(len(mips), cfg.Concurrency, func(_, i int) error {
+			data, _, _, err := bcn.EncodeImageWithOptions(mips[i], cfg.Format, cfg.EncodeOptions)
+			if err != nil {
+				return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, i, err)
+			}
+			payloads[i] = data
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, i, err)
+			return err
 		}
-		payloads[i] = data
 	}
 
 	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
@@ -338,7 +404,7 @@ func writeWithOptions(
 		return err
 	}
 
-	return writeFromBlocks(path, cfg.Format, width, height, payloads, compression)
+	return writeFromBlocks(path, cfg.Format, cfg.ColorSpace, width, height, payloads, compression, cfg.Concurrency)
 }
 
 // writeWithOptions writes img to w using Encoder-owned reusable buffers.
@@ -348,7 +414,7 @@ func (e *Encoder) writeWithOptions(
 	opts *WriteOptions,
 ) error {
 	cfg := normalizeWriteOptions(opts)
-	if err := validateSwizzleProfile(cfg.SwizzleProfile); err != nil {
+	if err := validateWriteOptions(&cfg); err != nil {
 		return err
 	}
 
@@ -356,57 +422,112 @@ func (e *Encoder) writeWithOptions(
 	width := bounds.Dx()
 	height := bounds.Dy()
 
-	mipMapCount, err := calculateMipMapCount(width, height)
+	mipMapCount, err := writeMipMapCount(width, height, cfg.MaxMipMaps)
+	if err != nil {
+		return err
+	}
+
+	payloads, err := e.encodeMipPayloads(img, mipMapCount, &cfg)
+	if err != nil {
+		return err
+	}
+
+	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
 	if err != nil {
 		return err
 	}
-	if cfg.MaxMipMaps > 0 && cfg.MaxMipMaps < mipMapCount {
-		mipMapCount = cfg.MaxMipMaps
+
+	return e.writeFromBlocks(w, cfg.Format, cfg.ColorSpace, width, height, texture2D, payloads, compression, cfg.Concurrency)
+}
+
+// encodeMipPayloads generates mipMapCount levels from img and encodes them with cfg.
+// The returned payloads are Encoder-owned and valid until the next call.
+func (e *Encoder) encodeMipPayloads(img image.Image, mipMapCount int, cfg *WriteOptions) ([][]byte, error) {
+	if isHDRFormat(cfg.Format) {
+		return encodeHDRImages(generateRGBAF32Mipmaps(toRGBAF32(img), mipMapCount, cfg.MipFilter), cfg)
 	}
-	if mipMapCount < 1 {
-		mipMapCount = 1
+
+	// Reusing the level buffers lets batch encoders retain them across images.
+	e.mips = generateMipmaps(e.mips, img, mipMapCount, cfg)
+	return e.encodeImages(e.mips, cfg)
+}
+
+// encodeImages applies the swizzle profile and encodes every image with cfg.
+// The returned payloads are Encoder-owned and valid until the next call.
+func (e *Encoder) encodeImages(mips []*image.NRGBA, cfg *WriteOptions) ([][]byte, error) {
+	if isHDRFormat(cfg.Format) {
+		hdrMips := make([]*RGBAF32, len(mips))
+		for i, mip := range mips {
+			hdrMips[i] = toRGBAF32(mip)
+		}
+		return encodeHDRImages(hdrMips, cfg)
+	}
+	if err := bcnFormat(cfg.Format); err != nil {
+		return nil, err
 	}
 
-	// BCn owns mip generation; using the Into variant lets batch encoders retain buffers.
-	e.mips = bcn.GenerateMipmapsInto(e.mips, img, mipMapCount, false)
-	mips := e.mips
-	if cfg.SwizzleProfile != SwizzleProfileNone {
-		e.swizzledMips = ensureImageSlots(e.swizzledMips, len(e.mips))
-		for i, mip := range e.mips {
+	// Every level owns its swizzle and payload slots, so levels can run in parallel.
+	swizzle := cfg.SwizzleProfile != SwizzleProfileNone
+	if swizzle {
+		e.swizzledMips = ensureImageSlots(e.swizzledMips, len(mips))
+	}
+	e.payloads = ensurePayloadSlots(e.payloads, len(mips))
+	payloads := e.payloads[:len(mips)]
+	err := forEachLevel(len(mips), cfg.Concurrency, func(_, i int) error {
+		mip := mips[i]
+		if swizzle {
 			swizzled, err := applySwizzleProfileInto(e.swizzledMips[i], mip, cfg.SwizzleProfile)
 			if err != nil {
 				return err
 			}
 			e.swizzledMips[i] = swizzled
+			mip = swizzled
 		}
-		mips = e.swizzledMips
-	}
 
-	e.payloads = ensurePayloadSlots(e.payloads, len(mips))
-	payloads := e.payloads[:len(mips)]
-	for i, mip := range mips {
 		data, _, _, err := bcn.EncodeImageInto(payloads[i], mip, cfg.Format, cfg.EncodeOptions)
 		if err != nil {
 			return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, i, err)
 		}
 		payloads[i] = data
-	}
-
-	compression, err := normalizeCompressionOptions(cfg.Compression, cfg.Compress)
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return e.writeFromBlocks(w, cfg.Format, width, height, payloads, compression)
+	return payloads, nil
 }
 
-// writeFromBlocks validates pre-encoded mipmaps and writes an EDDS container.
+// writeFromBlocks validates pre-encoded mipmaps and writes an EDDS file.
+// The temporary file is wrapped in a StreamWriter, so blocks stream to it one at a time.
 func writeFromBlocks(
 	path string,
 	format bcn.Format,
+	colorSpace ColorSpace,
 	width, height int,
 	mipmaps [][]byte,
 	compression normalizedCompressionOptions,
+	workers int,
+) error {
+	enc := NewEncoder()
+	return writeFileAtomic(path, func(f *os.File) error {
+		return enc.writeFromBlocks(NewStreamWriter(f), format, colorSpace, width, height, texture2D, mipmaps, compression, workers)
+	})
+}
+
+// writeFromBlocks validates pre-encoded mipmaps and writes an EDDS stream.
+// Every mipmap holds all surfaces of its level as described by layout.
+// Up to workers levels are compressed in parallel.
+// A serial write to a StreamWriter streams block by block, see streamBlocks.
+func (e *Encoder) writeFromBlocks(
+	w io.Writer,
+	format bcn.Format,
+	colorSpace ColorSpace,
+	width, height int,
+	layout textureLayout,
+	mipmaps [][]byte,
+	compression normalizedCompressionOptions,
+	workers int,
 ) error {
 	if len(mipmaps) == 0 {
 		return ErrEmptyMipmaps
@@ -415,7 +536,6 @@ func writeFromBlocks(
 		return ErrInvalidFormat
 	}
 
-	// convert dimensions to uint32
 	w32, err := u32FromInt(width)
 	if err != nil {
 		return err
@@ -429,157 +549,235 @@ func writeFromBlocks(
 		return err
 	}
 
-	// create DDS header
-	header, err := makeDDSHeader(w32, h32, mip32, format)
+	header, dx10, err := makeDDSHeaders(w32, h32, mip32, format, colorSpace, layout)
 	if err != nil {
 		return err
 	}
 
-	// Build all block descriptors before opening the output file because the table precedes payload data.
-	blocks := make([]*Block, len(mipmaps))
 	for i, mip := range mipmaps {
-		mipW := mipDimension(width, i)
-		mipH := mipDimension(height, i)
-		expected := expectedDataLength(format, mipW, mipH)
-		if expected <= 0 {
-			return ErrInvalidFormat
+		if err := checkMipPayload(format, width, height, layout, i, mip); err != nil {
+			return err
 		}
-		if len(mip) != expected {
-			return fmt.Errorf("%w: mipmap %d: expected %d, got %d", ErrMipmapSizeMismatch, i, expected, len(mip))
+	}
+	if sw, ok := w.(*StreamWriter); ok && workers <= 1 {
+		start, err := sw.ws.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrSeekOutput, err)
 		}
+		return e.streamBlocks(sw.ws, start, header, dx10, mipmaps, compression)
+	}
 
+	// Build all block descriptors before writing because the table precedes payload data.
+	e.blocks = ensureBlockSlots(e.blocks, len(mipmaps))
+	e.blockPayloads = ensurePayloadSlots(e.blockPayloads, len(mipmaps))
+	workers = max(1, min(workers, len(mipmaps)))
+	if len(e.compressors) < workers {
+		e.compressors = append(e.compressors, make([]blockCompressor, workers-len(e.compressors))...)
+	}
+	blocks := e.blocks[:len(mipmaps)]
+	err = forEachLevel(len(mipmaps), workers, func(worker, i int) error {
+		mip := mipmaps[i]
 		if compression.mode != CompressionNone {
-			block, err := compressBlockWithOptions(mip, compression)
+			block, payload, err := e.compressors[worker].compressBlock(e.blockPayloads[i], mip, compression)
 			if err != nil {
 				return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, i, err)
 			}
+			e.blockPayloads[i] = payload
 			blocks[i] = block
-		} else {
-			size, err := i32FromInt(len(mip))
-			if err != nil {
-				return err
-			}
-			blocks[i] = &Block{Magic: BlockMagicCOPY, Size: size, Data: mip}
+			return nil
 		}
-	}
 
-	return writeFileAtomic(path, func(f *os.File) error {
-		if err := bcn.WriteDDSMagic(f); err != nil {
-			return fmt.Errorf("%w: %v", ErrWriteDDSMagic, err)
-		}
-		if err := bcn.WriteDDSHeader(f, header); err != nil {
-			return fmt.Errorf("%w: %v", ErrWriteDDSHeader, err)
+		size, err := i32FromInt(len(mip))
+		if err != nil {
+			return err
 		}
+		blocks[i] = &Block{Magic: BlockMagicCOPY, Size: size, Data: mip}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		// EDDS stores mip table entries from smallest to largest mip.
-		for i, v := range slices.Backward(blocks) {
-			block := v
-			if _, err := f.Write([]byte(block.Magic)); err != nil {
-				return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockMagic, i, err)
-			}
-			if err := binary.Write(f, binary.LittleEndian, block.Size); err != nil {
-				return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockSize, i, err)
-			}
-		}
+	if err := writeDDSHeaders(w, header, dx10); err != nil {
+		return err
+	}
 
-		// Payload order mirrors the table order.
-		for i, v := range slices.Backward(blocks) {
-			if err := writeBlockData(f, v); err != nil {
-				return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockData, i, err)
-			}
-		}
+	return writeBlocks(w, blocks)
+}
 
-		return nil
-	})
+// StreamWriter opts an io.WriteSeeker into streaming writes. Encoders given
+// a StreamWriter reserve the block table, write every block as soon as it is
+// compressed and seek back to fill in the table, so memory scales with one block
+// instead of the whole chain. The output bytes are the same as a buffered write.
+//
+// The underlying writer must allow overwriting earlier bytes: a file opened
+// with O_APPEND fails with ErrSeekOutput after the stream is written, and so does
+// a writer whose Seek fails. Writes with WriteOptions.Concurrency above 1
+// compress the whole chain first and then write it sequentially.
+// Plain io.Writer outputs, including an *os.File passed directly, always take
+// the buffered path. The Write* file functions stream to their temporary file.
+type StreamWriter struct {
+	ws io.WriteSeeker
 }
 
-// writeFromBlocks validates pre-encoded mipmaps and writes an EDDS stream.
-func (e *Encoder) writeFromBlocks(
-	w io.Writer,
-	format bcn.Format,
-	width, height int,
+// NewStreamWriter returns a StreamWriter over ws.
+func NewStreamWriter(ws io.WriteSeeker) *StreamWriter {
+	return &StreamWriter{ws: ws}
+}
+
+// Write writes p to the underlying writer.
+func (s *StreamWriter) Write(p []byte) (int, error) {
+	return s.ws.Write(p)
+}
+
+// streamBlocks writes the EDDS stream to ws starting at offset start.
+// It reserves the block table, writes every block body as soon as it is compressed
+// and seeks back to fill in the table, so memory scales with the largest block
+// instead of the whole chain. ws must allow overwriting earlier bytes;
+// an append-only file fails with ErrSeekOutput after the table is written.
+// mipmaps must already be validated.
+func (e *Encoder) streamBlocks(
+	ws io.WriteSeeker,
+	start int64,
+	header *bcn.DDSHeader,
+	dx10 *bcn.DDSHeaderDX10,
 	mipmaps [][]byte,
 	compression normalizedCompressionOptions,
 ) error {
-	if len(mipmaps) == 0 {
-		return ErrEmptyMipmaps
+	if len(e.compressors) == 0 {
+		e.compressors = make([]blockCompressor, 1)
 	}
-	if format == bcn.FormatUnknown {
-		return ErrInvalidFormat
-	}
-
-	w32, err := u32FromInt(width)
-	if err != nil {
-		return err
-	}
-	h32, err := u32FromInt(height)
+	// Every level compresses into the same buffer, which ends sized for level 0.
+	e.blockPayloads = ensurePayloadSlots(e.blockPayloads, 1)
+	stream, err := startBlockStream(ws, start, header, dx10, len(mipmaps), compression, &e.compressors[0], e.blockPayloads[0])
 	if err != nil {
 		return err
 	}
-	mip32, err := u32FromInt(len(mipmaps))
-	if err != nil {
-		return err
+	for i, mip := range slices.Backward(mipmaps) {
+		if err := stream.write(i, mip); err != nil {
+			return err
+		}
 	}
+	e.blockPayloads[0] = stream.payload
 
-	header, err := makeDDSHeader(w32, h32, mip32, format)
-	if err != nil {
-		return err
-	}
+	return stream.finish()
+}
 
-	// Build all block descriptors before writing because the table precedes payload data.
-	e.blocks = ensureBlockSlots(e.blocks, len(mipmaps))
-	e.blockPayloads = ensurePayloadSlots(e.blockPayloads, len(mipmaps))
-	blocks := e.blocks[:len(mipmaps)]
-	for i, mip := range mipmaps {
-		mipW := mipDimension(width, i)
-		mipH := mipDimension(height, i)
-		expected := expectedDataLength(format, mipW, mipH)
-		if expected <= 0 {
-			return ErrInvalidFormat
+// blockStream writes EDDS blocks to ws in file order, from the smallest mip,
+// and fills in the reserved block table once every block is written.
+type blockStream struct {
+	ws          io.WriteSeeker
+	tableOffset int64
+	table       []blockHeader
+	compression normalizedCompressionOptions
+	compressor  *blockCompressor
+	// payload is the compressed body buffer reused for every block.
+	payload []byte
+}
+
+// startBlockStream writes the DDS headers at start, the current offset of ws,
+// and reserves a block table for count mip levels.
+func startBlockStream(
+	ws io.WriteSeeker,
+	start int64,
+	header *bcn.DDSHeader,
+	dx10 *bcn.DDSHeaderDX10,
+	count int,
+	compression normalizedCompressionOptions,
+	compressor *blockCompressor,
+	payload []byte,
+) (*blockStream, error) {
+	if err := writeDDSHeaders(ws, header, dx10); err != nil {
+		return nil, err
+	}
+	tableOffset := start + int64(4+bcn.DDSHeaderSize)
+	if dx10 != nil {
+		tableOffset += 20
+	}
+
+	// A zeroed table has no valid magic, so an interrupted stream is rejected by readers.
+	if _, err := ws.Write(make([]byte, count*8)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWriteBlockMagic, err)
+	}
+
+	return &blockStream{
+		ws:          ws,
+		tableOffset: tableOffset,
+		table:       make([]blockHeader, count),
+		compression: compression,
+		compressor:  compressor,
+		payload:     payload,
+	}, nil
+}
+
+// write compresses mip and writes it as the block of the given level.
+// Levels must arrive in file order, from the smallest mip.
+func (s *blockStream) write(level int, mip []byte) error {
+	block := &Block{Magic: BlockMagicCOPY, Data: mip}
+	if s.compression.mode != CompressionNone {
+		var err error
+		block, s.payload, err = s.compressor.compressBlock(s.payload, mip, s.compression)
+		if err != nil {
+			return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, level, err)
 		}
-		if len(mip) != expected {
-			return fmt.Errorf("%w: mipmap %d: expected %d, got %d", ErrMipmapSizeMismatch, i, expected, len(mip))
+	} else {
+		size, err := i32FromInt(len(mip))
+		if err != nil {
+			return err
 		}
+		block.Size = size
+	}
 
-		if compression.mode != CompressionNone {
-			block, payload, err := e.compressor.compressBlock(e.blockPayloads[i], mip, compression)
-			if err != nil {
-				return fmt.Errorf("%w: mipmap %d: %v", ErrCompressMipmap, i, err)
-			}
-			e.blockPayloads[i] = payload
-			blocks[i] = block
-		} else {
-			size, err := i32FromInt(len(mip))
-			if err != nil {
-				return err
-			}
-			blocks[i] = &Block{Magic: BlockMagicCOPY, Size: size, Data: mip}
+	if err := writeBlockData(s.ws, block); err != nil {
+		return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockData, level, err)
+	}
+	s.table[level] = blockHeader{Magic: block.Magic, Size: block.Size}
+	return nil
+}
+
+// finish seeks back to fill in the block table and leaves ws at the end of the stream.
+func (s *blockStream) finish() error {
+	end, err := s.ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSeekOutput, err)
+	}
+	if _, err := s.ws.Seek(s.tableOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("%w: %v", ErrSeekOutput, err)
+	}
+	for i, h := range slices.Backward(s.table) {
+		if err := writeBlockHeader(s.ws, i, h); err != nil {
+			return err
 		}
 	}
 
-	if err := bcn.WriteDDSMagic(w); err != nil {
-		return fmt.Errorf("%w: %v", ErrWriteDDSMagic, err)
+	// An append-only writer put the table after the bodies instead of over the placeholder.
+	tableSize := int64(len(s.table) * 8)
+	patched, err := s.ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSeekOutput, err)
 	}
-	if err := bcn.WriteDDSHeader(w, header); err != nil {
-		return fmt.Errorf("%w: %v", ErrWriteDDSHeader, err)
+	if patched != s.tableOffset+tableSize {
+		return fmt.Errorf("%w: block table written at %d, want %d", ErrSeekOutput, patched-tableSize, s.tableOffset)
 	}
 
-	// EDDS stores mip table entries from smallest to largest mip.
-	for i, v := range slices.Backward(blocks) {
-		block := v
-		if _, err := w.Write([]byte(block.Magic)); err != nil {
-			return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockMagic, i, err)
-		}
-		if err := binary.Write(w, binary.LittleEndian, block.Size); err != nil {
-			return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockSize, i, err)
-		}
+	// Leave ws at the end of the stream like a sequential write would.
+	if _, err := s.ws.Seek(end, io.SeekStart); err != nil {
+		return fmt.Errorf("%w: %v", ErrSeekOutput, err)
 	}
 
-	// Payload order mirrors the table order.
-	for i, v := range slices.Backward(blocks) {
-		if err := writeBlockData(w, v); err != nil {
-			return fmt.Errorf("%w: mipmap %d: %v", ErrWriteBlockData, i, err)
-		}
+	return nil
+}
+
+// checkMipPayload validates the size of the payload of mipmap level,
+// which holds every surface of the level as described by layout.
+func checkMipPayload(format bcn.Format, width, height int, layout textureLayout, level int, mip []byte) error {
+	expected := expectedDataLength(format, mipDimension(width, level), mipDimension(height, level))
+	if expected <= 0 {
+		return ErrInvalidFormat
+	}
+	expected *= layout.surfaces(level)
+	if len(mip) != expected {
+		return fmt.Errorf("%w: mipmap %d: expected %d, got %d", ErrMipmapSizeMismatch, level, expected, len(mip))
 	}
 
 	return nil
@@ -608,3 +806,20 @@ func ensureBlockSlots(slots []*Block, n int) []*Block {
 
 	return slots[:n]
 }
+
+// writeDDSHeaders writes the DDS magic, header, and optional DX10 header.
+func writeDDSHeaders(w io.Writer, header *bcn.DDSHeader, dx10 *bcn.DDSHeaderDX10) error {
+	if err := bcn.WriteDDSMagic(w); err != nil {
+		return fmt.Errorf("%w: %v", ErrWriteDDSMagic, err)
+	}
+	if err := bcn.WriteDDSHeader(w, header); err != nil {
+		return fmt.Errorf("%w: %v", ErrWriteDDSHeader, err)
+	}
+	if dx10 != nil {
+		if err := binary.Write(w, binary.LittleEndian, dx10); err != nil {
+			return fmt.Errorf("%w: %v", ErrWriteDX10Header, err)
+		}
+	}
+
+	return nil
+}